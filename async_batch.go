@@ -0,0 +1,192 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"github.com/dreamdata-io/facebook/internal"
+	"iter"
+)
+
+type AsyncRequestStatus = string
+
+// Statuses of requests in an async batch.
+const (
+	AsyncRequestInitial            AsyncRequestStatus = "INITIAL"
+	AsyncRequestInProgress         AsyncRequestStatus = "IN_PROGRESS"
+	AsyncRequestSuccess            AsyncRequestStatus = "SUCCESS"
+	AsyncRequestError              AsyncRequestStatus = "ERROR"
+	AsyncRequestCanceled           AsyncRequestStatus = "CANCELED"
+	AsyncRequestPendingDependency  AsyncRequestStatus = "PENDING_DEPENDENCY"
+	AsyncRequestCanceledDependency AsyncRequestStatus = "CANCELED_DEPENDENCY"
+	AsyncRequestErrorDependency    AsyncRequestStatus = "ERROR_DEPENDENCY"
+	AsyncRequestErrorConflicts     AsyncRequestStatus = "ERROR_CONFLICTS"
+)
+
+type AsyncBatchAPI interface {
+	SubmitAsyncBatch(ctx context.Context, adAccountID string, name string, requests ...BatchRequest) (string, error)
+	AsyncBatch(ctx context.Context, asyncBatchID string) (AsyncBatch, error)
+	WaitAsyncBatch(ctx context.Context, asyncBatchID string, opts PollOptions) (AsyncBatch, error)
+	AsyncBatchRequests(ctx context.Context, asyncBatchID string, statuses ...AsyncRequestStatus) iter.Seq2[AsyncBatchRequest, error]
+}
+
+// AsyncBatch is the status of an async batch, a.k.a. an ad async request set.
+type AsyncBatch struct {
+	ID              string `facebook:"id,required" json:"id"`
+	Name            string `json:"name"`
+	IsCompleted     bool   `json:"is_completed"`
+	TotalCount      int    `json:"total_count"`
+	InitialCount    int    `json:"initial_count"`
+	InProgressCount int    `json:"in_progress_count"`
+	SuccessCount    int    `json:"success_count"`
+	ErrorCount      int    `json:"error_count"`
+	CanceledCount   int    `json:"canceled_count"`
+}
+
+var asyncBatchFields = []string{"id", "name", "is_completed", "total_count", "initial_count",
+	"in_progress_count", "success_count", "error_count", "canceled_count"}
+
+// AsyncBatchRequest is a single request of an async batch.
+type AsyncBatchRequest struct {
+	ID            string             `facebook:"id,required" json:"id"`
+	Status        AsyncRequestStatus `json:"status"`
+	ScopeObjectID string             `json:"scope_object_id"`
+	Input         Result             `json:"input"`
+	Result        Result             `json:"result"`
+}
+
+var asyncBatchRequestFields = []string{"id", "status", "scope_object_id", "input", "result"}
+
+// Err returns the error of a failed request, or nil if the request didn't fail.
+// The error is a *Error if facebook reports error details in the result.
+func (r AsyncBatchRequest) Err() error {
+	switch r.Status {
+	case AsyncRequestError, AsyncRequestErrorDependency, AsyncRequestErrorConflicts,
+		AsyncRequestCanceled, AsyncRequestCanceledDependency:
+	default:
+		return nil
+	}
+
+	if r.Result != nil {
+		if err := r.Result.Err(); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("facebook: async batch request %s ends with status %s", r.ID, r.Status)
+}
+
+// AsyncBatchError is returned by WaitAsyncBatch if some requests of a completed async batch
+// failed or were canceled. Use AsyncBatchRequests to read the failed requests.
+type AsyncBatchError struct {
+	ID            string // ID of the async batch.
+	ErrorCount    int
+	CanceledCount int
+}
+
+func (e *AsyncBatchError) Error() string {
+	return fmt.Sprintf("facebook: async batch %s completes with %d failed and %d canceled requests", e.ID, e.ErrorCount, e.CanceledCount)
+}
+
+// SubmitAsyncBatch calls the Facebook API with POST at /act_{ad_account_id}/async_batch_requests
+// to run requests asynchronously, and returns the ID of the async batch.
+//
+// Requests are encoded like batch requests. Facebook only supports POST requests in async batches,
+// without dependencies or attached files; requests without a method are sent as POST.
+func (c *Client) SubmitAsyncBatch(ctx context.Context, adAccountID string, name string, requests ...BatchRequest) (string, error) {
+	adBatch := make([]Params, 0, len(requests))
+
+	for i, request := range requests {
+		if request.DependsOn != "" || len(request.AttachedFiles) > 0 {
+			return "", fmt.Errorf("facebook: async batch request %d has dependencies or attached files", i)
+		}
+
+		if request.Method != "" && request.Method != internal.POST {
+			return "", fmt.Errorf("facebook: async batch request %d uses method %s; only POST is supported", i, request.Method)
+		}
+
+		// async batches only update or create objects, so the body is always sent as form data.
+		request.Method = internal.POST
+		p, err := request.params()
+		if err != nil {
+			return "", err
+		}
+
+		delete(p, "method")
+		adBatch = append(adBatch, p)
+	}
+
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/act_%s/async_batch_requests", adAccountID), Params{
+		"name":    name,
+		"adbatch": adBatch,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeFieldAs[string](res, "id")
+}
+
+// AsyncBatch calls the Facebook API with GET at /{async_batch_id} to get the status of an async batch.
+func (c *Client) AsyncBatch(ctx context.Context, asyncBatchID string) (AsyncBatch, error) {
+	res, err := c.session.WithContext(ctx).Get(fmt.Sprintf("/%s", asyncBatchID), FieldsParams(asyncBatchFields...))
+	if err != nil {
+		return AsyncBatch{}, err
+	}
+
+	return DecodeAs[AsyncBatch](res)
+}
+
+// WaitAsyncBatch polls an async batch until it completes.
+// It returns an *AsyncBatchError along with the status if some requests failed or were canceled.
+func (c *Client) WaitAsyncBatch(ctx context.Context, asyncBatchID string, opts PollOptions) (AsyncBatch, error) {
+	var batch AsyncBatch
+
+	err := opts.poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		batch, err = c.AsyncBatch(ctx, asyncBatchID)
+		return batch.IsCompleted, err
+	})
+	if err != nil {
+		return batch, err
+	}
+
+	if batch.ErrorCount > 0 || batch.CanceledCount > 0 {
+		return batch, &AsyncBatchError{
+			ID:            batch.ID,
+			ErrorCount:    batch.ErrorCount,
+			CanceledCount: batch.CanceledCount,
+		}
+	}
+
+	return batch, nil
+}
+
+// AsyncBatchRequests calls the Facebook API with GET at /{async_batch_id}/requests and iterates over
+// requests of an async batch through all pages. If statuses are given, only requests in these statuses are read.
+func (c *Client) AsyncBatchRequests(ctx context.Context, asyncBatchID string, statuses ...AsyncRequestStatus) iter.Seq2[AsyncBatchRequest, error] {
+	return func(yield func(AsyncBatchRequest, error) bool) {
+		params := FieldsParams(asyncBatchRequestFields...)
+
+		if len(statuses) > 0 {
+			params["statuses"] = statuses
+		}
+
+		res, err := c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/requests", asyncBatchID), params)
+		if err != nil {
+			yield(AsyncBatchRequest{}, err)
+			return
+		}
+
+		pr, err := c.Paging(ctx, res)
+		if err != nil {
+			yield(AsyncBatchRequest{}, err)
+			return
+		}
+
+		for request, err := range All[AsyncBatchRequest](pr, PagingLimit{}) {
+			if !yield(request, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package facebook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dreamdata-io/facebook/internal"
+)
+
+func TestSubmitAsyncBatchMethod(t *testing.T) {
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+
+	for _, method := range []Method{internal.GET, internal.DELETE} {
+		_, err := client.SubmitAsyncBatch(context.Background(), "1", "batch", BatchRequest{Method: method, RelativeURL: "2"})
+
+		if err == nil || !strings.Contains(err.Error(), "only POST") {
+			t.Fatalf("non-POST request should be rejected. [method:%v] [e:%v]", method, err)
+		}
+	}
+
+	if numCalls != 0 {
+		t.Fatalf("rejected batch must not reach server. [calls:%v]", numCalls)
+	}
+
+	id, err := client.SubmitAsyncBatch(context.Background(), "1", "batch", BatchRequest{RelativeURL: "2", Body: Params{"name": "x"}})
+
+	if err != nil || id != "1" {
+		t.Fatalf("request without method should be sent as POST. [id:%v] [e:%v]", id, err)
+	}
+}
@@ -0,0 +1,290 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type EventSourceType = string
+
+// Types of event sources of website and engagement audiences.
+const (
+	PixelEventSource     EventSourceType = "pixel"
+	AppEventSource       EventSourceType = "app"
+	PageEventSource      EventSourceType = "page"
+	InstagramEventSource EventSourceType = "ig_business"
+	LeadFormEventSource  EventSourceType = "lead"
+	VideoEventSource     EventSourceType = "video"
+)
+
+// Subtypes of audiences defined by a rule.
+const (
+	WebsiteAudienceSubtype    AudienceSubtype = "WEBSITE"
+	EngagementAudienceSubtype AudienceSubtype = "ENGAGEMENT"
+)
+
+// AudienceRuleOperator combines rule filters, or compares a field or an aggregation with a value.
+type AudienceRuleOperator string
+
+// Operators of rule filters and aggregations.
+const (
+	AudienceRuleAnd AudienceRuleOperator = "and"
+	AudienceRuleOr  AudienceRuleOperator = "or"
+
+	AudienceRuleEq             AudienceRuleOperator = "eq"
+	AudienceRuleNeq            AudienceRuleOperator = "neq"
+	AudienceRuleContains       AudienceRuleOperator = "contains"
+	AudienceRuleNotContains    AudienceRuleOperator = "not_contains"
+	AudienceRuleIContains      AudienceRuleOperator = "i_contains"
+	AudienceRuleINotContains   AudienceRuleOperator = "i_not_contains"
+	AudienceRuleStartsWith     AudienceRuleOperator = "starts_with"
+	AudienceRuleIStartsWith    AudienceRuleOperator = "i_starts_with"
+	AudienceRuleRegexMatch     AudienceRuleOperator = "regex_match"
+	AudienceRuleIsAny          AudienceRuleOperator = "is_any"
+	AudienceRuleIsNotAny       AudienceRuleOperator = "is_not_any"
+	AudienceRuleGreater        AudienceRuleOperator = ">"
+	AudienceRuleGreaterOrEqual AudienceRuleOperator = ">="
+	AudienceRuleLess           AudienceRuleOperator = "<"
+	AudienceRuleLessOrEqual    AudienceRuleOperator = "<="
+	AudienceRuleEqual          AudienceRuleOperator = "="
+	AudienceRuleNotEqual       AudienceRuleOperator = "!="
+)
+
+type AudienceRuleAggregationType string
+
+// Types of rule aggregations.
+const (
+	AudienceRuleCount     AudienceRuleAggregationType = "count"
+	AudienceRuleSum       AudienceRuleAggregationType = "sum"
+	AudienceRuleAvg       AudienceRuleAggregationType = "avg"
+	AudienceRuleTimeSpent AudienceRuleAggregationType = "time_spent"
+)
+
+// maxRetention is the longest period a rule can look back, by event source type.
+var maxRetention = map[EventSourceType]time.Duration{
+	PixelEventSource:     180 * 24 * time.Hour,
+	AppEventSource:       180 * 24 * time.Hour,
+	PageEventSource:      365 * 24 * time.Hour,
+	InstagramEventSource: 365 * 24 * time.Hour,
+	LeadFormEventSource:  90 * 24 * time.Hour,
+	VideoEventSource:     365 * 24 * time.Hour,
+}
+
+var conditionOperators = map[AudienceRuleOperator]bool{
+	AudienceRuleEq: true, AudienceRuleNeq: true, AudienceRuleContains: true, AudienceRuleNotContains: true, AudienceRuleIContains: true,
+	AudienceRuleINotContains: true, AudienceRuleStartsWith: true, AudienceRuleIStartsWith: true, AudienceRuleRegexMatch: true,
+	AudienceRuleIsAny: true, AudienceRuleIsNotAny: true, AudienceRuleGreater: true, AudienceRuleGreaterOrEqual: true, AudienceRuleLess: true,
+	AudienceRuleLessOrEqual: true, AudienceRuleEqual: true, AudienceRuleNotEqual: true,
+}
+
+var comparisonOperators = map[AudienceRuleOperator]bool{
+	AudienceRuleGreater: true, AudienceRuleGreaterOrEqual: true, AudienceRuleLess: true, AudienceRuleLessOrEqual: true, AudienceRuleEqual: true, AudienceRuleNotEqual: true,
+}
+
+// AudienceRule is the rule of a website or engagement audience.
+// See https://developers.facebook.com/docs/marketing-api/audiences/guides/website-custom-audiences.
+//
+// Rules are built with Include and Exclude, e.g.
+//
+//	rule := facebook.AudienceRule{}.
+//		Include(facebook.NewAudienceRuleEntry(30*24*time.Hour, facebook.AudienceRuleEventSource{ID: pixelID, Type: facebook.PixelEventSource}).
+//			Where(facebook.AudienceRuleCondition("url", facebook.AudienceRuleIContains, "shoes"))).
+//		Exclude(facebook.NewAudienceRuleEntry(7*24*time.Hour, facebook.AudienceRuleEventSource{ID: pixelID, Type: facebook.PixelEventSource}).
+//			Where(facebook.AudienceRuleCondition("event", facebook.AudienceRuleEq, "Purchase")))
+type AudienceRule struct {
+	Inclusions *AudienceRuleSet `json:"inclusions,omitempty"`
+	Exclusions *AudienceRuleSet `json:"exclusions,omitempty"`
+}
+
+// AudienceRuleSet combines rules with AudienceRuleOr or AudienceRuleAnd.
+type AudienceRuleSet struct {
+	Operator AudienceRuleOperator `json:"operator"`
+	Rules    []AudienceRuleEntry  `json:"rules"`
+}
+
+// AudienceRuleEntry selects people who triggered events of the event sources within the retention period.
+type AudienceRuleEntry struct {
+	EventSources     []AudienceRuleEventSource `json:"event_sources"`
+	RetentionSeconds int64                     `json:"retention_seconds"`
+	Filter           *AudienceRuleFilter       `json:"filter,omitempty"`
+	Aggregation      *AudienceRuleAggregation  `json:"aggregation,omitempty"`
+}
+
+type AudienceRuleEventSource struct {
+	ID   string          `json:"id"`
+	Type EventSourceType `json:"type"`
+}
+
+// AudienceRuleFilter is either a condition on a field, or a group of filters combined with AudienceRuleAnd or AudienceRuleOr.
+type AudienceRuleFilter struct {
+	Operator AudienceRuleOperator `json:"operator"`
+	Filters  []AudienceRuleFilter `json:"filters,omitempty"` // set on groups.
+	Field    string               `json:"field,omitempty"`   // set on conditions, e.g. "url", "event" or a parameter name.
+	Value    interface{}          `json:"value,omitempty"`   // set on conditions.
+}
+
+// AudienceRuleAggregation keeps people whose events aggregate to a value matching the operator, e.g. people
+// who visited more than 3 times.
+type AudienceRuleAggregation struct {
+	Type     AudienceRuleAggregationType `json:"type"`
+	Field    string                      `json:"field,omitempty"` // aggregated parameter of AudienceRuleSum and AudienceRuleAvg.
+	Method   string                      `json:"method,omitempty"`
+	Operator AudienceRuleOperator        `json:"operator"`
+	Value    interface{}                 `json:"value"`
+}
+
+// NewAudienceRuleEntry creates a rule looking back retention at events of sources.
+func NewAudienceRuleEntry(retention time.Duration, sources ...AudienceRuleEventSource) AudienceRuleEntry {
+	return AudienceRuleEntry{
+		EventSources:     sources,
+		RetentionSeconds: int64(retention / time.Second),
+	}
+}
+
+// Where keeps events matching all filters.
+func (r AudienceRuleEntry) Where(filters ...AudienceRuleFilter) AudienceRuleEntry {
+	filter := AudienceRuleAll(filters...)
+	r.Filter = &filter
+	return r
+}
+
+// Aggregate keeps people whose events match the aggregation.
+func (r AudienceRuleEntry) Aggregate(aggregation AudienceRuleAggregation) AudienceRuleEntry {
+	r.Aggregation = &aggregation
+	return r
+}
+
+// AudienceRuleCondition creates a filter comparing field with value.
+func AudienceRuleCondition(field string, operator AudienceRuleOperator, value interface{}) AudienceRuleFilter {
+	return AudienceRuleFilter{Field: field, Operator: operator, Value: value}
+}
+
+// AudienceRuleAll creates a filter matching all filters.
+func AudienceRuleAll(filters ...AudienceRuleFilter) AudienceRuleFilter {
+	return AudienceRuleFilter{Operator: AudienceRuleAnd, Filters: filters}
+}
+
+// AudienceRuleAny creates a filter matching any of filters.
+func AudienceRuleAny(filters ...AudienceRuleFilter) AudienceRuleFilter {
+	return AudienceRuleFilter{Operator: AudienceRuleOr, Filters: filters}
+}
+
+// Include adds rules to the inclusions. People matching any inclusion rule are in the audience.
+func (a AudienceRule) Include(rules ...AudienceRuleEntry) AudienceRule {
+	a.Inclusions = appendRules(a.Inclusions, rules)
+	return a
+}
+
+// Exclude adds rules to the exclusions. People matching any exclusion rule are removed from the audience.
+func (a AudienceRule) Exclude(rules ...AudienceRuleEntry) AudienceRule {
+	a.Exclusions = appendRules(a.Exclusions, rules)
+	return a
+}
+
+func appendRules(set *AudienceRuleSet, rules []AudienceRuleEntry) *AudienceRuleSet {
+	if set == nil {
+		return &AudienceRuleSet{Operator: AudienceRuleOr, Rules: rules}
+	}
+
+	return &AudienceRuleSet{Operator: set.Operator, Rules: append(set.Rules[:len(set.Rules):len(set.Rules)], rules...)}
+}
+
+// Validate checks the rule without sending it. It reports missing inclusions, unknown operators,
+// retention periods facebook doesn't support and incomplete filters or aggregations.
+func (a AudienceRule) Validate() error {
+	if a.Inclusions == nil || len(a.Inclusions.Rules) == 0 {
+		return errors.New("facebook: audience rule has no inclusion")
+	}
+
+	if err := a.Inclusions.validate("inclusions"); err != nil {
+		return err
+	}
+
+	if a.Exclusions != nil {
+		return a.Exclusions.validate("exclusions")
+	}
+
+	return nil
+}
+
+func (s *AudienceRuleSet) validate(path string) error {
+	if s.Operator != AudienceRuleAnd && s.Operator != AudienceRuleOr {
+		return fmt.Errorf("facebook: invalid operator %q of audience rule %s", s.Operator, path)
+	}
+
+	for i, rule := range s.Rules {
+		if err := rule.validate(fmt.Sprintf("%s.rules[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r AudienceRuleEntry) validate(path string) error {
+	if len(r.EventSources) == 0 {
+		return fmt.Errorf("facebook: audience rule %s has no event source", path)
+	}
+
+	for _, source := range r.EventSources {
+		limit, ok := maxRetention[source.Type]
+		if !ok || source.ID == "" {
+			return fmt.Errorf("facebook: invalid event source %+v of audience rule %s", source, path)
+		}
+
+		if retention := time.Duration(r.RetentionSeconds) * time.Second; retention <= 0 || retention > limit {
+			return fmt.Errorf("facebook: retention %v of audience rule %s is not within %v for %s", retention, path, limit, source.Type)
+		}
+	}
+
+	if r.Filter != nil {
+		if err := r.Filter.validate(path + ".filter"); err != nil {
+			return err
+		}
+	}
+
+	if a := r.Aggregation; a != nil {
+		switch a.Type {
+		case AudienceRuleCount, AudienceRuleTimeSpent:
+		case AudienceRuleSum, AudienceRuleAvg:
+			if a.Field == "" {
+				return fmt.Errorf("facebook: %s aggregation of audience rule %s has no field", a.Type, path)
+			}
+		default:
+			return fmt.Errorf("facebook: invalid aggregation type %q of audience rule %s", a.Type, path)
+		}
+
+		if !comparisonOperators[a.Operator] || a.Value == nil {
+			return fmt.Errorf("facebook: invalid aggregation comparison %q %v of audience rule %s", a.Operator, a.Value, path)
+		}
+	}
+
+	return nil
+}
+
+func (f *AudienceRuleFilter) validate(path string) error {
+	if f.Operator == AudienceRuleAnd || f.Operator == AudienceRuleOr {
+		if len(f.Filters) == 0 || f.Field != "" {
+			return fmt.Errorf("facebook: audience rule filter group %s must have filters and no field", path)
+		}
+
+		for i := range f.Filters {
+			if err := f.Filters[i].validate(fmt.Sprintf("%s.filters[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if !conditionOperators[f.Operator] {
+		return fmt.Errorf("facebook: invalid operator %q of audience rule filter %s", f.Operator, path)
+	}
+
+	if f.Field == "" || f.Value == nil || len(f.Filters) > 0 {
+		return fmt.Errorf("facebook: audience rule condition %s must have a field and a value", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goldenAudienceRule is the rule of the README, as facebook expects it.
+const goldenAudienceRule = `{
+	"inclusions": {
+		"operator": "or",
+		"rules": [{
+			"event_sources": [{"id": "123", "type": "pixel"}],
+			"retention_seconds": 2592000,
+			"filter": {"operator": "and", "filters": [{"operator": "i_contains", "field": "url", "value": "shoes"}]}
+		}]
+	},
+	"exclusions": {
+		"operator": "or",
+		"rules": [{
+			"event_sources": [{"id": "123", "type": "pixel"}],
+			"retention_seconds": 604800,
+			"filter": {"operator": "and", "filters": [{"operator": "eq", "field": "event", "value": "Purchase"}]}
+		}]
+	}
+}`
+
+func TestAudienceRuleMarshalJSON(t *testing.T) {
+	golden := &bytes.Buffer{}
+	if err := json.Compact(golden, []byte(goldenAudienceRule)); err != nil {
+		t.Fatalf("invalid golden json. [e:%v]", err)
+	}
+
+	pixel := AudienceRuleEventSource{ID: "123", Type: PixelEventSource}
+	rule := AudienceRule{}.
+		Include(NewAudienceRuleEntry(30*24*time.Hour, pixel).Where(AudienceRuleCondition("url", AudienceRuleIContains, "shoes"))).
+		Exclude(NewAudienceRuleEntry(7*24*time.Hour, pixel).Where(AudienceRuleCondition("event", AudienceRuleEq, "Purchase")))
+
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("rule should be valid. [e:%v]", err)
+	}
+
+	data, err := json.Marshal(rule)
+
+	if err != nil || string(data) != golden.String() {
+		t.Fatalf("unexpected json. [e:%v] [expected:%s] [actual:%s]", err, golden, data)
+	}
+
+	// builders return copies, so that a rule can be extended without changing it.
+	_ = rule.Include(NewAudienceRuleEntry(time.Hour, pixel))
+
+	if len(rule.Inclusions.Rules) != 1 {
+		t.Fatalf("rule should not be changed by extending it. [inclusions:%v]", len(rule.Inclusions.Rules))
+	}
+}
+
+func TestAudienceRuleValidate(t *testing.T) {
+	pixel := AudienceRuleEventSource{ID: "123", Type: PixelEventSource}
+	lead := AudienceRuleEventSource{ID: "456", Type: LeadFormEventSource}
+	day := 24 * time.Hour
+
+	// err is a part of the expected error, empty for valid rules.
+	cases := []struct {
+		name string
+		rule AudienceRule
+		err  string
+	}{
+		{
+			name: "valid",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(180*day, pixel).
+				Where(AudienceRuleAny(AudienceRuleCondition("url", AudienceRuleIContains, "shoes"), AudienceRuleCondition("url", AudienceRuleIContains, "boots"))).
+				Aggregate(AudienceRuleAggregation{Type: AudienceRuleSum, Field: "value", Operator: AudienceRuleGreater, Value: 100})),
+		},
+		{name: "no inclusion", rule: AudienceRule{}.Exclude(NewAudienceRuleEntry(day, pixel)), err: "has no inclusion"},
+		{name: "empty inclusions", rule: AudienceRule{Inclusions: &AudienceRuleSet{Operator: AudienceRuleOr}}, err: "has no inclusion"},
+		{name: "no event source", rule: AudienceRule{}.Include(NewAudienceRuleEntry(day)), err: "has no event source"},
+		{
+			name: "unknown event source type",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, AudienceRuleEventSource{ID: "123", Type: "website"})),
+			err:  "invalid event source",
+		},
+		{name: "pixel retention over 180 days", rule: AudienceRule{}.Include(NewAudienceRuleEntry(181*day, pixel)), err: "is not within 4320h0m0s for pixel"},
+		{name: "lead retention of 90 days", rule: AudienceRule{}.Include(NewAudienceRuleEntry(90*day, lead))},
+		{name: "lead retention over 90 days", rule: AudienceRule{}.Include(NewAudienceRuleEntry(91*day, lead)), err: "is not within 2160h0m0s for lead"},
+		{name: "no retention", rule: AudienceRule{}.Include(NewAudienceRuleEntry(0, pixel)), err: "is not within"},
+		{
+			name: "invalid exclusion",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel)).Exclude(NewAudienceRuleEntry(181*day, pixel)),
+			err:  "audience rule exclusions.rules[0] is not within",
+		},
+		{
+			name: "filter group with a field",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Where(AudienceRuleFilter{
+				Operator: AudienceRuleAnd, Field: "url", Filters: []AudienceRuleFilter{AudienceRuleCondition("url", AudienceRuleEq, "x")},
+			})),
+			err: "filter group inclusions.rules[0].filter.filters[0] must have filters and no field",
+		},
+		{name: "empty filter group", rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Where()), err: "must have filters and no field"},
+		{
+			name: "condition without value",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Where(AudienceRuleCondition("url", AudienceRuleEq, nil))),
+			err:  "must have a field and a value",
+		},
+		{
+			name: "condition without field",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Where(AudienceRuleCondition("", AudienceRuleEq, "x"))),
+			err:  "must have a field and a value",
+		},
+		{
+			name: "unknown condition operator",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Where(AudienceRuleCondition("url", "like", "x"))),
+			err:  `invalid operator "like"`,
+		},
+		{
+			name: "count aggregation",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: AudienceRuleCount, Operator: AudienceRuleGreaterOrEqual, Value: 3})),
+		},
+		{
+			name: "sum aggregation without field",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: AudienceRuleSum, Operator: AudienceRuleGreater, Value: 1})),
+			err:  "sum aggregation of audience rule inclusions.rules[0] has no field",
+		},
+		{
+			name: "avg aggregation without field",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: AudienceRuleAvg, Operator: AudienceRuleGreater, Value: 1})),
+			err:  "avg aggregation of audience rule inclusions.rules[0] has no field",
+		},
+		{
+			name: "aggregation with a non-comparison operator",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: AudienceRuleCount, Operator: AudienceRuleContains, Value: 1})),
+			err:  `invalid aggregation comparison "contains"`,
+		},
+		{
+			name: "aggregation without value",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: AudienceRuleCount, Operator: AudienceRuleGreater})),
+			err:  "invalid aggregation comparison",
+		},
+		{
+			name: "unknown aggregation type",
+			rule: AudienceRule{}.Include(NewAudienceRuleEntry(day, pixel).Aggregate(AudienceRuleAggregation{Type: "max", Operator: AudienceRuleGreater, Value: 1})),
+			err:  `invalid aggregation type "max"`,
+		},
+	}
+
+	for _, c := range cases {
+		err := c.rule.Validate()
+
+		if c.err == "" && err != nil || c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("unexpected validation. [case:%v] [expected:%v] [e:%v]", c.name, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package facebook
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AudienceSchemaKey is a column of customer data added to a Custom Audience.
+// See https://developers.facebook.com/docs/marketing-api/audiences/guides/custom-audiences#hash.
+type AudienceSchemaKey string
+
+const (
+	SchemaEmail              AudienceSchemaKey = "EMAIL"
+	SchemaPhone              AudienceSchemaKey = "PHONE" // including country code.
+	SchemaFirstName          AudienceSchemaKey = "FN"
+	SchemaLastName           AudienceSchemaKey = "LN"
+	SchemaFirstInitial       AudienceSchemaKey = "FI"
+	SchemaBirthYear          AudienceSchemaKey = "DOBY" // YYYY.
+	SchemaBirthMonth         AudienceSchemaKey = "DOBM" // MM.
+	SchemaBirthDay           AudienceSchemaKey = "DOBD" // DD.
+	SchemaGender             AudienceSchemaKey = "GEN"
+	SchemaCity               AudienceSchemaKey = "CT"
+	SchemaState              AudienceSchemaKey = "ST"
+	SchemaZip                AudienceSchemaKey = "ZIP"
+	SchemaCountry            AudienceSchemaKey = "COUNTRY"   // 2-letter ISO 3166-1 alpha-2 country code.
+	SchemaMobileAdvertiserID AudienceSchemaKey = "MADID"     // sent unhashed.
+	SchemaExternalID         AudienceSchemaKey = "EXTERN_ID" // sent unhashed.
+)
+
+type audienceColumn struct {
+	hash      bool
+	normalize func(string) string
+	validate  func(string) error
+}
+
+var audienceColumns = map[AudienceSchemaKey]audienceColumn{
+	SchemaEmail:              {hash: true, normalize: normalizeEmail, validate: validateEmail},
+	SchemaPhone:              {hash: true, normalize: normalizePhone, validate: validateDigits(7, 15)},
+	SchemaFirstName:          {hash: true, normalize: normalizeName},
+	SchemaLastName:           {hash: true, normalize: normalizeName},
+	SchemaFirstInitial:       {hash: true, normalize: normalizeFirstInitial, validate: validateLength(1)},
+	SchemaBirthYear:          {hash: true, normalize: normalizeDate, validate: validateNumber(4, 1900, 9999)},
+	SchemaBirthMonth:         {hash: true, normalize: normalizeDate, validate: validateNumber(2, 1, 12)},
+	SchemaBirthDay:           {hash: true, normalize: normalizeDate, validate: validateNumber(2, 1, 31)},
+	SchemaGender:             {hash: true, normalize: normalizeGender, validate: validateLength(1)},
+	SchemaCity:               {hash: true, normalize: normalizeCity},
+	SchemaState:              {hash: true, normalize: normalizeState},
+	SchemaZip:                {hash: true, normalize: normalizeZip},
+	SchemaCountry:            {hash: true, normalize: normalizeCountry, validate: validateLength(2)},
+	SchemaMobileAdvertiserID: {normalize: normalizeMobileAdvertiserID},
+	SchemaExternalID:         {normalize: normalizeExternalID},
+}
+
+// AudienceRowError reports a row rejected by AudiencePayloadBuilder.
+type AudienceRowError struct {
+	Row    int               // index of the row among all rows passed to AudiencePayloadBuilder.Add, UploadUsers or read from a CustomerFile.
+	Line   int               // line of the row in a CustomerFile, starting at 1. zero if the row doesn't come from a file.
+	Column AudienceSchemaKey // empty if the row as a whole is invalid.
+	Err    error
+}
+
+func (e *AudienceRowError) Error() string {
+	where := fmt.Sprintf("audience row %d", e.Row)
+	if e.Line > 0 {
+		where = fmt.Sprintf("line %d", e.Line)
+	}
+
+	if e.Column == "" {
+		return fmt.Sprintf("facebook: invalid %s; %v", where, e.Err)
+	}
+
+	return fmt.Sprintf("facebook: invalid %s in %s; %v", e.Column, where, e.Err)
+}
+
+func (e *AudienceRowError) Unwrap() error {
+	return e.Err
+}
+
+// AudiencePayloadBuilder normalizes, hashes and validates customer data into an AddUserPayload.
+//
+// Columns are normalized following Meta's rules and hashed with SHA-256, except MADID and EXTERN_ID
+// which are only normalized. Values which are already hashed are kept as is.
+type AudiencePayloadBuilder struct {
+	schema  []AudienceSchemaKey
+	data    [][]any
+	numRows int
+}
+
+// NewAudiencePayloadBuilder creates a builder for rows with the columns in schema.
+func NewAudiencePayloadBuilder(schema ...AudienceSchemaKey) (*AudiencePayloadBuilder, error) {
+	if err := validateAudienceSchema(schema); err != nil {
+		return nil, err
+	}
+
+	return &AudiencePayloadBuilder{schema: schema}, nil
+}
+
+func validateAudienceSchema(schema []AudienceSchemaKey) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("facebook: audience schema is empty")
+	}
+
+	seen := make(map[AudienceSchemaKey]bool, len(schema))
+
+	for _, key := range schema {
+		if _, ok := audienceColumns[key]; !ok {
+			return fmt.Errorf("facebook: unknown audience schema key %q", key)
+		}
+
+		if seen[key] {
+			return fmt.Errorf("facebook: duplicated audience schema key %q", key)
+		}
+
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// Schema returns the columns of the builder.
+func (b *AudiencePayloadBuilder) Schema() []AudienceSchemaKey {
+	return b.schema
+}
+
+// Add normalizes, hashes and validates a row with one value per schema column.
+// Empty values are allowed as long as the row has at least one value.
+// An invalid row is not added and an *AudienceRowError is returned.
+func (b *AudiencePayloadBuilder) Add(values ...string) error {
+	row := b.numRows
+	b.numRows++
+
+	data, err := formatAudienceRow(b.schema, values)
+	if err != nil {
+		err.Row = row
+		return err
+	}
+
+	b.data = append(b.data, data)
+	return nil
+}
+
+// formatAudienceRow normalizes, hashes and validates values of the schema columns.
+func formatAudienceRow(schema []AudienceSchemaKey, values []string) ([]any, *AudienceRowError) {
+	if len(values) != len(schema) {
+		return nil, &AudienceRowError{Err: fmt.Errorf("expect %d values but get %d", len(schema), len(values))}
+	}
+
+	data := make([]any, len(values))
+	empty := true
+
+	for i, key := range schema {
+		v, err := audienceColumns[key].format(values[i])
+		if err != nil {
+			return nil, &AudienceRowError{Column: key, Err: err}
+		}
+
+		data[i] = v
+		empty = empty && v == ""
+	}
+
+	if empty {
+		return nil, &AudienceRowError{Err: fmt.Errorf("all values are empty")}
+	}
+
+	return data, nil
+}
+
+// Len returns the number of rows added to the builder.
+func (b *AudiencePayloadBuilder) Len() int {
+	return len(b.data)
+}
+
+// Payload returns all rows added to the builder.
+func (b *AudiencePayloadBuilder) Payload() AddUserPayload {
+	schema := make([]string, len(b.schema))
+
+	for i, key := range b.schema {
+		schema[i] = string(key)
+	}
+
+	return AddUserPayload{
+		Schema: schema,
+		Data:   b.data,
+	}
+}
+
+// Reset removes all rows from the builder. Row indexes of errors keep counting.
+func (b *AudiencePayloadBuilder) Reset() {
+	b.data = nil
+}
+
+func (c audienceColumn) format(v string) (string, error) {
+	if c.hash {
+		if hashed := strings.ToLower(strings.TrimSpace(v)); sha256Pattern.MatchString(hashed) {
+			return hashed, nil
+		}
+	}
+
+	v = c.normalize(v)
+
+	if v == "" {
+		return "", nil
+	}
+
+	if c.validate != nil {
+		if err := c.validate(v); err != nil {
+			return "", err
+		}
+	}
+
+	if !c.hash {
+		return v, nil
+	}
+
+	return sha256Hex(v), nil
+}
+
+// normalizeFirstInitial keeps the first letter of a lowercase first name.
+func normalizeFirstInitial(v string) string {
+	for _, r := range normalizeName(v) {
+		return string(r)
+	}
+
+	return ""
+}
+
+// normalizeMobileAdvertiserID lowercases an IDFA or Android advertising ID, keeping hyphens.
+func normalizeMobileAdvertiserID(v string) string {
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+func validateEmail(v string) error {
+	at := strings.IndexByte(v, '@')
+
+	if at <= 0 || !strings.Contains(v[at+1:], ".") || strings.ContainsFunc(v, unicode.IsSpace) {
+		return fmt.Errorf("%q is not an email address", v)
+	}
+
+	return nil
+}
+
+func validateLength(n int) func(string) error {
+	return func(v string) error {
+		if len(v) != n {
+			return fmt.Errorf("%q is not %d characters long after normalization", v, n)
+		}
+
+		return nil
+	}
+}
+
+func validateDigits(min, max int) func(string) error {
+	return func(v string) error {
+		if len(v) < min || len(v) > max {
+			return fmt.Errorf("%q must have %d to %d digits", v, min, max)
+		}
+
+		return nil
+	}
+}
+
+func validateNumber(digits, min, max int) func(string) error {
+	return func(v string) error {
+		var n int
+
+		if len(v) != digits {
+			return fmt.Errorf("%q must have %d digits", v, digits)
+		}
+
+		for _, r := range v {
+			n = n*10 + int(r-'0')
+		}
+
+		if n < min || n > max {
+			return fmt.Errorf("%q must be between %d and %d", v, min, max)
+		}
+
+		return nil
+	}
+}
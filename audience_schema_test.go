@@ -0,0 +1,152 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAudienceColumnFormat(t *testing.T) {
+	hashed := sha256Hex("john.doe@example.com")
+
+	cases := []struct {
+		key      AudienceSchemaKey
+		value    string
+		expected string // normalized value, hashed unless the column isn't.
+		err      bool
+	}{
+		{key: SchemaEmail, value: " John.Doe@Example.com", expected: "john.doe@example.com"},
+		{key: SchemaEmail, value: "john.doe", err: true},
+		{key: SchemaEmail, value: hashed, expected: hashed},
+		{key: SchemaEmail, value: strings.ToUpper(hashed), expected: hashed},
+		{key: SchemaPhone, value: "+1 (650) 555-1212", expected: "16505551212"},
+		{key: SchemaPhone, value: "0045 12 34 56 78", expected: "4512345678"},
+		{key: SchemaPhone, value: "123", err: true},
+		{key: SchemaFirstName, value: "Mary-Ann", expected: "maryann"},
+		{key: SchemaLastName, value: "O'Brien Jr.", expected: "obrienjr"},
+		{key: SchemaFirstInitial, value: "John", expected: "j"},
+		{key: SchemaBirthYear, value: "1990", expected: "1990"},
+		{key: SchemaBirthYear, value: "90", err: true},
+		{key: SchemaBirthMonth, value: "01", expected: "01"},
+		{key: SchemaBirthMonth, value: "13", err: true},
+		{key: SchemaBirthMonth, value: "1", err: true},
+		{key: SchemaBirthDay, value: "31", expected: "31"},
+		{key: SchemaBirthDay, value: "32", err: true},
+		{key: SchemaGender, value: "Female", expected: "f"},
+		{key: SchemaGender, value: "other", expected: ""},
+		{key: SchemaCity, value: "New York", expected: "newyork"},
+		{key: SchemaState, value: "CA", expected: "ca"},
+		{key: SchemaZip, value: "94025-1234", expected: "94025"},
+		{key: SchemaZip, value: "00-950", expected: "00950"},
+		{key: SchemaZip, value: "SW1A 1AA", expected: "sw1a1aa"},
+		{key: SchemaCountry, value: "US", expected: "us"},
+		{key: SchemaCountry, value: "USA", err: true},
+		{key: SchemaMobileAdvertiserID, value: " AB12CD34-5678-90EF-AB12-CD3456789012", expected: "ab12cd34-5678-90ef-ab12-cd3456789012"},
+		{key: SchemaExternalID, value: " User-42 ", expected: "User-42"},
+		{key: SchemaExternalID, value: hashed, expected: hashed},
+		{key: SchemaEmail, value: "  ", expected: ""},
+	}
+
+	for _, c := range cases {
+		column := audienceColumns[c.key]
+		actual, err := column.format(c.value)
+
+		if c.err {
+			if err == nil {
+				t.Fatalf("value should be invalid. [key:%v] [value:%q] [actual:%v]", c.key, c.value, actual)
+			}
+
+			continue
+		}
+
+		expected := c.expected
+		if column.hash && expected != "" && expected != hashed {
+			expected = sha256Hex(expected)
+		}
+
+		if err != nil || actual != expected {
+			t.Fatalf("unexpected value. [key:%v] [value:%q] [expected:%v] [actual:%v] [e:%v]", c.key, c.value, expected, actual, err)
+		}
+	}
+}
+
+func TestNewAudiencePayloadBuilderErrors(t *testing.T) {
+	cases := map[string][]AudienceSchemaKey{
+		"is empty":   nil,
+		"unknown":    {SchemaEmail, "FOO"},
+		"duplicated": {SchemaEmail, SchemaPhone, SchemaEmail},
+	}
+
+	for expected, schema := range cases {
+		if _, err := NewAudiencePayloadBuilder(schema...); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("unexpected error. [schema:%v] [expected:%v] [e:%v]", schema, expected, err)
+		}
+	}
+}
+
+func TestAudiencePayloadBuilder(t *testing.T) {
+	builder, err := NewAudiencePayloadBuilder(SchemaEmail, SchemaCountry, SchemaExternalID)
+
+	if err != nil {
+		t.Fatalf("cannot create builder. [e:%v]", err)
+	}
+
+	rows := []struct {
+		values []string
+		column AudienceSchemaKey // column of the error, "-" for a row error and "" if valid.
+	}{
+		{values: []string{"a@example.com", "US", "1"}},
+		{values: []string{"invalid", "US", "2"}, column: SchemaEmail},
+		{values: []string{"", "", ""}, column: "-"},
+		{values: []string{"b@example.com", "DK"}, column: "-"},
+		{values: []string{"", "", "3"}},
+	}
+
+	for i, row := range rows {
+		err := builder.Add(row.values...)
+
+		if row.column == "" {
+			if err != nil {
+				t.Fatalf("row should be valid. [row:%v] [e:%v]", i, err)
+			}
+
+			continue
+		}
+
+		var rowErr *AudienceRowError
+		column := row.column
+		if column == "-" {
+			column = ""
+		}
+
+		if !errors.As(err, &rowErr) || rowErr.Row != i || rowErr.Column != column {
+			t.Fatalf("unexpected row error. [row:%v] [column:%v] [e:%v]", i, column, err)
+		}
+	}
+
+	payload := builder.Payload()
+
+	if fmt.Sprint(payload.Schema) != "[EMAIL COUNTRY EXTERN_ID]" || builder.Len() != 2 {
+		t.Fatalf("unexpected payload. [payload:%v]", payload)
+	}
+
+	expected := fmt.Sprint([][]any{
+		{sha256Hex("a@example.com"), sha256Hex("us"), "1"},
+		{"", "", "3"},
+	})
+
+	if fmt.Sprint(payload.Data) != expected {
+		t.Fatalf("unexpected payload data. [expected:%v] [actual:%v]", expected, payload.Data)
+	}
+
+	builder.Reset()
+
+	if err := builder.Add("x"); err == nil || err.(*AudienceRowError).Row != len(rows) {
+		t.Fatalf("row indexes should keep counting after reset. [e:%v]", err)
+	}
+
+	if builder.Len() != 0 {
+		t.Fatalf("reset should remove all rows. [len:%v]", builder.Len())
+	}
+}
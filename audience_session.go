@@ -0,0 +1,94 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type AudienceSessionStage = string
+
+// Stages of an audience session which is still running. Any other stage is final.
+const (
+	AudienceSessionUploading  AudienceSessionStage = "uploading"
+	AudienceSessionProcessing AudienceSessionStage = "processing"
+)
+
+// AudienceSession is the status of a session adding, replacing or removing users of an audience.
+// See https://developers.facebook.com/docs/marketing-api/reference/custom-audience/sessions.
+type AudienceSession struct {
+	SessionID         string               `json:"session_id"`
+	Stage             AudienceSessionStage `json:"stage"`
+	NumReceived       int                  `json:"num_received"`
+	NumMatched        int                  `json:"num_matched"`
+	NumInvalidEntries int                  `json:"num_invalid_entries"`
+	StartTime         int64                `json:"start_time"` // unix timestamp.
+	EndTime           int64                `json:"end_time"`   // unix timestamp, zero until the session ends.
+}
+
+// Started returns the start time of the session.
+func (s AudienceSession) Started() time.Time {
+	return time.Unix(s.StartTime, 0)
+}
+
+// Ended returns the end time of the session, or the zero time if the session is running.
+func (s AudienceSession) Ended() time.Time {
+	if s.EndTime == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(s.EndTime, 0)
+}
+
+// Done reports whether facebook finished processing the session.
+func (s AudienceSession) Done() bool {
+	switch s.Stage {
+	case AudienceSessionUploading, AudienceSessionProcessing:
+		return false
+	case "":
+		return s.EndTime != 0
+	default:
+		return true
+	}
+}
+
+// AudienceSession is Sessions decoded into an AudienceSession.
+func (c *Client) AudienceSession(ctx context.Context, audienceID string, sessionID string) (AudienceSession, error) {
+	session, ok, err := c.findAudienceSession(ctx, audienceID, sessionID)
+	if err == nil && !ok {
+		err = fmt.Errorf("facebook: audience session %s is not found", sessionID)
+	}
+
+	return session, err
+}
+
+// WaitForSession polls an audience session until facebook finishes processing it.
+// A session which is not listed yet is polled again, as facebook may list it with a delay.
+// Sessions of ReplaceUsers may take up to 90 minutes, so opts.Timeout should be set accordingly.
+func (c *Client) WaitForSession(ctx context.Context, audienceID string, sessionID string, opts PollOptions) (AudienceSession, error) {
+	var session AudienceSession
+
+	err := opts.poll(ctx, func(ctx context.Context) (bool, error) {
+		var ok bool
+		var err error
+		session, ok, err = c.findAudienceSession(ctx, audienceID, sessionID)
+		return ok && session.Done(), err
+	})
+
+	return session, err
+}
+
+func (c *Client) findAudienceSession(ctx context.Context, audienceID string, sessionID string) (AudienceSession, bool, error) {
+	sessions, err := decodeData[AudienceSession](c.Sessions(ctx, audienceID, sessionID, nil))
+	if err != nil {
+		return AudienceSession{}, false, err
+	}
+
+	for _, session := range sessions {
+		if session.SessionID == sessionID {
+			return session, true, nil
+		}
+	}
+
+	return AudienceSession{}, false, nil
+}
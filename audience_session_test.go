@@ -0,0 +1,77 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAudienceSessionDone(t *testing.T) {
+	cases := []struct {
+		session AudienceSession
+		done    bool
+	}{
+		{session: AudienceSession{Stage: AudienceSessionUploading}, done: false},
+		{session: AudienceSession{Stage: AudienceSessionProcessing, EndTime: 1700000000}, done: false},
+		{session: AudienceSession{Stage: "completed"}, done: true},
+		{session: AudienceSession{}, done: false},
+		{session: AudienceSession{EndTime: 1700000000}, done: true},
+	}
+
+	for _, c := range cases {
+		if c.session.Done() != c.done {
+			t.Fatalf("unexpected done. [session:%+v] [expected:%v]", c.session, c.done)
+		}
+	}
+
+	if !(AudienceSession{}).Ended().IsZero() || !(AudienceSession{EndTime: 1700000000}).Ended().Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("end time should be zero until the session ends.")
+	}
+}
+
+func TestWaitForSession(t *testing.T) {
+	// the session is listed from the second poll, and ends on the third.
+	responses := []string{
+		`{"data": [{"session_id": "9", "stage": "completed"}]}`,
+		`{"data": [{"session_id": "42", "stage": "processing", "num_received": 10}]}`,
+		`{"data": [{"session_id": "42", "stage": "", "num_received": 10, "num_matched": 8, "end_time": 1700000000}]}`,
+	}
+
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/sessions" || r.URL.Query().Get("session_id") != "42" {
+			t.Errorf("unexpected request. [url:%v]", r.URL)
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[min(numCalls, len(responses)-1)]))
+		numCalls++
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	session, err := client.WaitForSession(context.Background(), "1", "42", PollOptions{Interval: time.Millisecond})
+
+	if err != nil || numCalls != 3 || !session.Done() || session.NumMatched != 8 {
+		t.Fatalf("session should be polled until it ends. [calls:%v] [session:%+v] [e:%v]", numCalls, session, err)
+	}
+}
+
+func TestWaitForSessionTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data": [{"session_id": "42", "stage": "uploading"}]}`)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	_, err := client.WaitForSession(context.Background(), "1", "42", PollOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("polling should time out. [e:%v]", err)
+	}
+}
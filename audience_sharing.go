@@ -0,0 +1,186 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"github.com/dreamdata-io/facebook/internal"
+	"strings"
+)
+
+// AudienceShare is an ad account an audience is shared with.
+type AudienceShare struct {
+	AccountID     string `json:"account_id"`
+	AccountName   string `json:"account_name"`
+	BusinessID    string `json:"business_id"`
+	BusinessName  string `json:"business_name"`
+	SharingStatus string `json:"sharing_status"`
+}
+
+// AudienceShareOptions controls how an audience is shared.
+type AudienceShareOptions struct {
+	// RelationshipTypes describe the relationship between the businesses owning the audience and
+	// the ad accounts, e.g. "AGENCY", when the ad accounts belong to another business.
+	RelationshipTypes []string
+	Params            Params // extra params.
+}
+
+// AudienceAdAccounts calls the Facebook API with GET at /{audience_id}/adaccounts and returns the IDs
+// of all ad accounts which can use the audience.
+func (c *Client) AudienceAdAccounts(ctx context.Context, audienceID string) ([]string, error) {
+	res, err := c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/adaccounts", audienceID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := c.Paging(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for account, err := range All[struct {
+		ID string `facebook:"id,required"`
+	}](pr, PagingLimit{}) {
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, strings.TrimPrefix(account.ID, "act_"))
+	}
+
+	return ids, nil
+}
+
+// AudienceShares returns the ad accounts an audience is shared with, along with their businesses.
+func (c *Client) AudienceShares(ctx context.Context, audienceID string) ([]AudienceShare, error) {
+	res, err := c.Audience(ctx, audienceID, FieldsParams("shared_account_info"))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Get("shared_account_info") == nil {
+		return nil, nil
+	}
+
+	return DecodeFieldAs[[]AudienceShare](res, "shared_account_info")
+}
+
+// ShareAudience calls the Facebook API with POST at /{audience_id}/adaccounts to share an audience
+// with ad accounts. Sharing with ad accounts of another business requires opts.RelationshipTypes.
+func (c *Client) ShareAudience(ctx context.Context, audienceID string, adAccountIDs []string, opts AudienceShareOptions) error {
+	params := audienceShareParams(adAccountIDs, opts.Params)
+
+	if len(opts.RelationshipTypes) > 0 {
+		params["relationship_type"] = opts.RelationshipTypes
+	}
+
+	return c.shareAudience(ctx, internal.POST, audienceID, params)
+}
+
+// UnshareAudience calls the Facebook API with DELETE at /{audience_id}/adaccounts to stop sharing
+// an audience with ad accounts.
+func (c *Client) UnshareAudience(ctx context.Context, audienceID string, adAccountIDs []string) error {
+	return c.shareAudience(ctx, internal.DELETE, audienceID, audienceShareParams(adAccountIDs, nil))
+}
+
+// BusinessAdAccounts calls the Facebook API with GET at /{business_id}/owned_ad_accounts and returns
+// all ad accounts owned by a business.
+func (c *Client) BusinessAdAccounts(ctx context.Context, businessID string) ([]AdAccount, error) {
+	res, err := c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/owned_ad_accounts", businessID), FieldsParams(adAccountFields...))
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := c.Paging(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []AdAccount
+
+	for account, err := range All[AdAccount](pr, PagingLimit{}) {
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// ShareAudienceWithBusiness shares an audience with all ad accounts owned by a business, e.g. a client
+// of an agency. opts.RelationshipTypes is required when the business doesn't own the audience.
+func (c *Client) ShareAudienceWithBusiness(ctx context.Context, audienceID string, businessID string, opts AudienceShareOptions) error {
+	accounts, err := c.BusinessAdAccounts(ctx, businessID)
+	if err != nil {
+		return err
+	}
+
+	if len(accounts) == 0 {
+		return fmt.Errorf("facebook: business %s owns no ad account to share audience %s with", businessID, audienceID)
+	}
+
+	ids := make([]string, len(accounts))
+	for i, account := range accounts {
+		ids[i] = account.ID
+	}
+
+	return c.ShareAudience(ctx, audienceID, ids, opts)
+}
+
+// UnshareAudienceWithBusiness stops sharing an audience with all ad accounts of a business.
+// It does nothing if the audience isn't shared with the business.
+func (c *Client) UnshareAudienceWithBusiness(ctx context.Context, audienceID string, businessID string) error {
+	shares, err := c.AudienceShares(ctx, audienceID)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+
+	for _, share := range shares {
+		if share.BusinessID == businessID {
+			ids = append(ids, share.AccountID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return c.UnshareAudience(ctx, audienceID, ids)
+}
+
+func (c *Client) shareAudience(ctx context.Context, method Method, audienceID string, params Params) error {
+	if ids, _ := params["adaccounts"].([]string); len(ids) == 0 {
+		return fmt.Errorf("facebook: no ad account to share audience %s with", audienceID)
+	}
+
+	res, err := c.session.WithContext(ctx).Api(fmt.Sprintf("/%s/adaccounts", audienceID), method, params)
+	if err != nil {
+		return err
+	}
+
+	if success, ok := res.Get("success").(bool); ok && !success {
+		return fmt.Errorf("facebook: cannot change sharing of audience %s", audienceID)
+	}
+
+	return nil
+}
+
+func audienceShareParams(adAccountIDs []string, extra Params) Params {
+	ids := make([]string, len(adAccountIDs))
+	for i, id := range adAccountIDs {
+		ids[i] = strings.TrimPrefix(id, "act_")
+	}
+
+	params := make(Params, len(extra)+2)
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	params["adaccounts"] = ids
+	return params
+}
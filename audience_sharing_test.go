@@ -0,0 +1,63 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShareAudienceWithBusiness(t *testing.T) {
+	var shared []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/b1/owned_ad_accounts" && r.URL.Query().Get("after") == "":
+			_, _ = fmt.Fprintf(w, `{"data": [{"id": "act_1"}], "paging": {"cursors": {"after": "c1"}, "next": "http://%s/b1/owned_ad_accounts?after=c1"}}`, r.Host)
+		case r.URL.Path == "/b1/owned_ad_accounts":
+			_, _ = w.Write([]byte(`{"data": [{"id": "act_2"}]}`))
+		case r.URL.Path == "/b2/owned_ad_accounts":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		case r.URL.Path == "/1/adaccounts":
+			shared = append(shared, r.FormValue("method")+" "+r.FormValue("adaccounts")+" "+r.FormValue("relationship_type"))
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case r.URL.Path == "/1":
+			_, _ = w.Write([]byte(`{"id": "1", "shared_account_info": [
+				{"account_id": "1", "business_id": "b1"},
+				{"account_id": "3", "business_id": "b3"},
+				{"account_id": "2", "business_id": "b1"}
+			]}`))
+		default:
+			t.Errorf("unexpected request. [path:%v]", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	ctx := context.Background()
+
+	if err := client.ShareAudienceWithBusiness(ctx, "1", "b1", AudienceShareOptions{RelationshipTypes: []string{"AGENCY"}}); err != nil {
+		t.Fatalf("share should succeed. [e:%v]", err)
+	}
+
+	if err := client.ShareAudienceWithBusiness(ctx, "1", "b2", AudienceShareOptions{}); err == nil {
+		t.Fatalf("share with a business without ad accounts should fail.")
+	}
+
+	if err := client.UnshareAudienceWithBusiness(ctx, "1", "b1"); err != nil {
+		t.Fatalf("unshare should succeed. [e:%v]", err)
+	}
+
+	if err := client.UnshareAudienceWithBusiness(ctx, "1", "b4"); err != nil {
+		t.Fatalf("unshare of a business without shares should do nothing. [e:%v]", err)
+	}
+
+	expected := fmt.Sprint([]string{` ["1","2"] ["AGENCY"]`, `DELETE ["1","2"] `})
+
+	if fmt.Sprint(shared) != expected {
+		t.Fatalf("unexpected sharing calls. [expected:%v] [actual:%v]", expected, shared)
+	}
+}
@@ -0,0 +1,111 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type TermsOfService = string
+
+// Terms of service reported in the tos_accepted field of an ad account or a business.
+const (
+	CustomAudienceTOS    TermsOfService = "custom_audience_tos"
+	WebCustomAudienceTOS TermsOfService = "web_custom_audience_tos"
+)
+
+// tosNotAcceptedSubcode is the error subcode facebook returns when Custom Audience terms are not accepted.
+const tosNotAcceptedSubcode = 1870034
+
+// TOSNotAcceptedError is returned by audience methods when the ad account, or the business owning it,
+// hasn't accepted the Custom Audience terms of service. Send users to TOSAcceptURL to accept them.
+type TOSNotAcceptedError struct {
+	AdAccountID string // empty if the ad account is unknown to the method.
+	Err         *Error
+}
+
+func (e *TOSNotAcceptedError) Error() string {
+	if e.AdAccountID == "" {
+		return "facebook: custom audience terms of service are not accepted"
+	}
+
+	return fmt.Sprintf("facebook: custom audience terms of service are not accepted for ad account %s", e.AdAccountID)
+}
+
+func (e *TOSNotAcceptedError) Unwrap() error {
+	return e.Err
+}
+
+// TOSAcceptURL returns the page where Custom Audience terms are accepted for an ad account.
+// businessID is optional and accepts the terms on behalf of the business owning the ad account.
+func TOSAcceptURL(adAccountID string, businessID string) string {
+	query := url.Values{"act": {strings.TrimPrefix(adAccountID, "act_")}}
+
+	if businessID != "" {
+		query.Set("business_id", businessID)
+	}
+
+	return "https://business.facebook.com/ads/manage/customaudiences/tos/?" + query.Encode()
+}
+
+// TOSAccepted calls the Facebook API with GET at /act_{ad_account_id} and returns the terms of service
+// accepted by the ad account. Terms accepted by the business owning the ad account are included.
+func (c *Client) TOSAccepted(ctx context.Context, adAccountID string) (map[TermsOfService]bool, error) {
+	return c.tosAccepted(ctx, fmt.Sprintf("/act_%s", adAccountID))
+}
+
+// BusinessTOSAccepted calls the Facebook API with GET at /{business_id} and returns the terms of service
+// accepted by the business on behalf of its ad accounts.
+func (c *Client) BusinessTOSAccepted(ctx context.Context, businessID string) (map[TermsOfService]bool, error) {
+	return c.tosAccepted(ctx, fmt.Sprintf("/%s", businessID))
+}
+
+func (c *Client) tosAccepted(ctx context.Context, path string) (map[TermsOfService]bool, error) {
+	res, err := c.session.WithContext(ctx).Get(path, FieldsParams("tos_accepted"))
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := map[TermsOfService]bool{}
+
+	if res.Get("tos_accepted") == nil {
+		return accepted, nil
+	}
+
+	tos, err := DecodeFieldAs[map[string]int](res, "tos_accepted")
+	if err != nil {
+		return nil, err
+	}
+
+	for name, v := range tos {
+		accepted[name] = v == 1
+	}
+
+	return accepted, nil
+}
+
+// CustomAudienceTOSAccepted reports whether the ad account accepted the Custom Audience terms of service.
+func (c *Client) CustomAudienceTOSAccepted(ctx context.Context, adAccountID string) (bool, error) {
+	accepted, err := c.TOSAccepted(ctx, adAccountID)
+	return accepted[CustomAudienceTOS], err
+}
+
+// checkTOS turns errors facebook returns when Custom Audience terms are not accepted into a *TOSNotAcceptedError.
+func checkTOS(err error, adAccountID string) error {
+	var fbErr *Error
+
+	if !errors.As(err, &fbErr) {
+		return err
+	}
+
+	message := strings.ToLower(fbErr.Message + " " + fbErr.UserTitle)
+
+	if fbErr.ErrorSubcode == tosNotAcceptedSubcode ||
+		(strings.Contains(message, "custom audience terms") && strings.Contains(message, "not accepted")) {
+		return &TOSNotAcceptedError{AdAccountID: adAccountID, Err: fbErr}
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTOSAccepted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields") != "tos_accepted" {
+			t.Errorf("tos_accepted should be requested. [query:%v]", r.URL.RawQuery)
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/act_1":
+			_, _ = w.Write([]byte(`{"id": "act_1", "tos_accepted": {"custom_audience_tos": 1, "web_custom_audience_tos": 0}}`))
+		case "/b1":
+			_, _ = w.Write([]byte(`{"id": "b1", "tos_accepted": {"custom_audience_tos": 1}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id": "b2"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	ctx := context.Background()
+
+	accepted, err := client.TOSAccepted(ctx, "1")
+	if err != nil || !accepted[CustomAudienceTOS] || accepted[WebCustomAudienceTOS] {
+		t.Fatalf("unexpected ad account terms. [accepted:%v] [e:%v]", accepted, err)
+	}
+
+	accepted, err = client.BusinessTOSAccepted(ctx, "b1")
+	if err != nil || !accepted[CustomAudienceTOS] {
+		t.Fatalf("unexpected business terms. [accepted:%v] [e:%v]", accepted, err)
+	}
+
+	accepted, err = client.BusinessTOSAccepted(ctx, "b2")
+	if err != nil || accepted == nil || len(accepted) != 0 {
+		t.Fatalf("business without terms should have none accepted. [accepted:%v] [e:%v]", accepted, err)
+	}
+}
+
+func TestCheckTOS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+
+		switch r.URL.Path {
+		case "/act_1/customaudiences", "/a1/users":
+			_, _ = w.Write([]byte(`{"error": {"message": "Permissions error", "type": "OAuthException", "code": 200, "error_subcode": 1870034}}`))
+		case "/a2/users":
+			_, _ = w.Write([]byte(`{"error": {"message": "Custom Audience Terms Not Accepted", "type": "OAuthException", "code": 200}}`))
+		default:
+			_, _ = w.Write([]byte(`{"error": {"message": "Invalid parameter", "type": "OAuthException", "code": 100}}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	ctx := context.Background()
+
+	var tosErr *TOSNotAcceptedError
+
+	_, err := client.CreateAudience(ctx, "1", Params{"name": "audience"})
+	if !errors.As(err, &tosErr) || tosErr.AdAccountID != "1" || tosErr.Err.ErrorSubcode != tosNotAcceptedSubcode {
+		t.Fatalf("create audience should fail with the ad account of unaccepted terms. [e:%v]", err)
+	}
+
+	for _, audienceID := range []string{"a1", "a2"} {
+		tosErr = nil
+
+		_, err = client.AddUsers(ctx, audienceID, AddUserPayload{Schema: []string{"EMAIL"}}, AddUserSession{SessionID: 1}, nil)
+		if !errors.As(err, &tosErr) || tosErr.AdAccountID != "" {
+			t.Fatalf("add users should fail with unaccepted terms. [audience:%v] [e:%v]", audienceID, err)
+		}
+	}
+
+	var fbErr *Error
+
+	_, err = client.AddUsers(ctx, "a3", AddUserPayload{Schema: []string{"EMAIL"}}, AddUserSession{SessionID: 1}, nil)
+	if errors.As(err, &tosErr) || !errors.As(err, &fbErr) || err != error(fbErr) || fbErr.Code != 100 {
+		t.Fatalf("other errors should be returned unchanged. [e:%v]", err)
+	}
+}
@@ -0,0 +1,236 @@
+package facebook
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"math/rand/v2"
+)
+
+// MaxAudienceUsersPerBatch is the maximum number of rows facebook accepts in a single call to /{audience_id}/users.
+const MaxAudienceUsersPerBatch = 10000
+
+// maxRejectedRows bounds the rejected rows kept in an AudienceUploadReport.
+const maxRejectedRows = 100
+
+type AudienceOperation = string
+
+// Operations of UploadUsers.
+const (
+	AddUsersOperation     AudienceOperation = "add"
+	ReplaceUsersOperation AudienceOperation = "replace"
+	RemoveUsersOperation  AudienceOperation = "remove"
+)
+
+type AudienceUploadOptions struct {
+	Operation         AudienceOperation // defaults to AddUsersOperation.
+	SessionID         int64             // random if zero.
+	BatchSize         int               // rows per call, at most MaxAudienceUsersPerBatch. defaults to MaxAudienceUsersPerBatch.
+	EstimatedNumTotal int               // estimated number of rows of the whole session, if known.
+	Params            Params            // extra params sent with every call.
+}
+
+// AudienceUploadReport aggregates the outcome of all batches sent by UploadUsers.
+//
+// NumReceived, NumInvalidEntries and InvalidEntrySamples of AddUserOutput are summed over all batches.
+type AudienceUploadReport struct {
+	AddUserOutput
+	NumBatches   int
+	NumRejected  int                 // rows rejected before being sent.
+	RejectedRows []*AudienceRowError // first rejected rows.
+}
+
+func (r *AudienceUploadReport) add(out AddUserOutput) {
+	r.NumBatches++
+	r.NumReceived += out.NumReceived
+	r.NumInvalidEntries += out.NumInvalidEntries
+
+	if out.AudienceId != "" {
+		r.AudienceId = out.AudienceId
+	}
+
+	for k, v := range out.InvalidEntrySamples {
+		if r.InvalidEntrySamples == nil {
+			r.InvalidEntrySamples = make(map[string]string)
+		}
+
+		r.InvalidEntrySamples[k] = v
+	}
+}
+
+func (r *AudienceUploadReport) reject(err *AudienceRowError) {
+	r.NumRejected++
+
+	if len(r.RejectedRows) < maxRejectedRows {
+		r.RejectedRows = append(r.RejectedRows, err)
+	}
+}
+
+// UploadUsers streams rows of raw customer data to an audience in a single multi-batch session.
+// opts.Operation selects whether rows are added, replace the audience or are removed from it.
+//
+// Rows have one value per schema column and are normalized and hashed with an AudiencePayloadBuilder.
+// Invalid rows, as well as *AudienceRowError yielded by rows, are skipped and reported.
+// Rows are sent in batches of at most MaxAudienceUsersPerBatch rows with increasing batch_seq,
+// and last_batch_flag is set on the last batch.
+//
+// If a batch fails, UploadUsers stops and returns the report so far along with the error.
+// The session is left open and report.SessionId tells which session it is.
+func (c *Client) UploadUsers(ctx context.Context, audienceID string, schema []AudienceSchemaKey, rows iter.Seq2[[]string, error], opts AudienceUploadOptions) (*AudienceUploadReport, error) {
+	return c.uploadUserRows(ctx, audienceID, schema, rows, nil, opts)
+}
+
+// uploadUserRows is UploadUsers where line, if set, returns the line of the last row yielded by rows,
+// so that invalid rows are reported with their line.
+func (c *Client) uploadUserRows(ctx context.Context, audienceID string, schema []AudienceSchemaKey, rows iter.Seq2[[]string, error], line func() int, opts AudienceUploadOptions) (*AudienceUploadReport, error) {
+	switch opts.Operation {
+	case "", AddUsersOperation, ReplaceUsersOperation, RemoveUsersOperation:
+	default:
+		return nil, fmt.Errorf("facebook: unknown audience operation %q", opts.Operation)
+	}
+
+	builder, err := NewAudiencePayloadBuilder(schema...)
+	if err != nil {
+		return nil, err
+	}
+
+	size := opts.BatchSize
+	if size <= 0 || size > MaxAudienceUsersPerBatch {
+		size = MaxAudienceUsersPerBatch
+	}
+
+	session := AddUserSession{
+		SessionID:         opts.SessionID,
+		EstimatedNumTotal: float64(opts.EstimatedNumTotal),
+	}
+
+	if session.SessionID == 0 {
+		session.SessionID = rand.Int64N(math.MaxInt64) + 1
+	}
+
+	report := &AudienceUploadReport{}
+	report.SessionId = fmt.Sprint(session.SessionID)
+
+	send := func(payload AddUserPayload, last bool) error {
+		session.BatchSeq++
+		session.LastBatchFlag = last
+
+		out, err := c.uploadUsers(ctx, opts.Operation, audienceID, payload, session, opts.Params)
+		if err != nil {
+			return fmt.Errorf("facebook: cannot upload batch %d of audience session %d; %w", session.BatchSeq, session.SessionID, err)
+		}
+
+		report.add(out)
+		return nil
+	}
+
+	// a full batch is held until the next valid row, as it is the last batch otherwise.
+	var held *AddUserPayload
+
+	row := -1
+
+	for values, err := range rows {
+		row++
+
+		var rowErr *AudienceRowError
+
+		if err == nil {
+			if err = builder.Add(values...); errors.As(err, &rowErr) {
+				rowErr.Row = row
+
+				if line != nil {
+					rowErr.Line = line()
+				}
+			}
+		}
+
+		if err != nil {
+			if !errors.As(err, &rowErr) {
+				return report, err
+			}
+
+			report.reject(rowErr)
+			continue
+		}
+
+		if held != nil {
+			if err := send(*held, false); err != nil {
+				return report, err
+			}
+
+			held = nil
+		}
+
+		if builder.Len() == size {
+			payload := builder.Payload()
+			held = &payload
+			builder.Reset()
+		}
+	}
+
+	if held != nil {
+		return report, send(*held, true)
+	}
+
+	if builder.Len() > 0 {
+		return report, send(builder.Payload(), true)
+	}
+
+	return report, nil
+}
+
+func (c *Client) uploadUsers(ctx context.Context, op AudienceOperation, audienceID string, payload AddUserPayload, session AddUserSession, params Params) (AddUserOutput, error) {
+	// AddUsers, ReplaceUsers and RemoveUsers set payload and session in params.
+	p := make(Params, len(params)+2)
+	for k, v := range params {
+		p[k] = v
+	}
+
+	var res Result
+	var err error
+
+	switch op {
+	case ReplaceUsersOperation:
+		res, err = c.ReplaceUsers(ctx, audienceID, payload, session, p)
+	case RemoveUsersOperation:
+		res, err = c.RemoveUsers(ctx, audienceID, payload, session, p)
+	default:
+		res, err = c.AddUsers(ctx, audienceID, payload, session, p)
+	}
+
+	if err != nil {
+		return AddUserOutput{}, err
+	}
+
+	return DecodeAs[AddUserOutput](res)
+}
+
+// CSVRows reads rows of a CSV stream, e.g. to send them with UploadUsers.
+// If hasHeader is set, the first line is skipped so that it isn't sent as a user.
+// Use NewCSVCustomerFile to map header columns to schema keys instead.
+// Iteration stops at the first malformed line.
+func CSVRows(r io.Reader, hasHeader bool) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+
+		for skip := hasHeader; ; skip = false {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+
+			if skip && err == nil {
+				continue
+			}
+
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dreamdata-io/facebook/internal"
+)
+
+type audienceUploadCall struct {
+	Path    string
+	Method  string
+	Session AddUserSession
+	Payload AddUserPayload
+}
+
+// newAudienceUploadTestServer records calls to /{audience_id}/users and answers with the number of rows received.
+func newAudienceUploadTestServer(t *testing.T, calls *[]audienceUploadCall) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := audienceUploadCall{Path: r.URL.Path, Method: r.FormValue("method")}
+
+		if err := json.Unmarshal([]byte(r.FormValue("session")), &call.Session); err != nil {
+			t.Errorf("invalid session param. [e:%v]", err)
+		}
+
+		if err := json.Unmarshal([]byte(r.FormValue("payload")), &call.Payload); err != nil {
+			t.Errorf("invalid payload param. [e:%v]", err)
+		}
+
+		*calls = append(*calls, call)
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"audience_id": "1", "session_id": "%d", "num_received": %d, "num_invalid_entries": 0}`,
+			call.Session.SessionID, len(call.Payload.Data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func audienceRows(values ...string) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for _, v := range values {
+			if !yield([]string{v}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestUploadUsersBatches(t *testing.T) {
+	cases := []struct {
+		name    string
+		rows    []string
+		batches string // sizes of batches.
+	}{
+		{name: "partial last batch", rows: []string{"a@x.io", "b@x.io", "c@x.io", "d@x.io", "e@x.io"}, batches: "[2 2 1]"},
+		{name: "full last batch", rows: []string{"a@x.io", "b@x.io", "c@x.io", "d@x.io"}, batches: "[2 2]"},
+		// the full batch is held while invalid rows follow, so that it's still flagged as last.
+		{name: "invalid rows after full batch", rows: []string{"a@x.io", "b@x.io", "invalid", ""}, batches: "[2]"},
+		{name: "single row", rows: []string{"a@x.io"}, batches: "[1]"},
+		{name: "no valid row", rows: []string{"invalid"}, batches: "[]"},
+	}
+
+	for _, c := range cases {
+		var calls []audienceUploadCall
+		srv := newAudienceUploadTestServer(t, &calls)
+		client := newTestClient(srv)
+
+		report, err := client.UploadUsers(context.Background(), "1", []AudienceSchemaKey{SchemaEmail}, audienceRows(c.rows...), AudienceUploadOptions{
+			SessionID:         42,
+			BatchSize:         2,
+			EstimatedNumTotal: len(c.rows),
+		})
+
+		if err != nil {
+			t.Fatalf("upload should succeed. [case:%v] [e:%v]", c.name, err)
+		}
+
+		sizes := []int{}
+		received := 0
+
+		for i, call := range calls {
+			sizes = append(sizes, len(call.Payload.Data))
+			received += len(call.Payload.Data)
+			session := call.Session
+
+			if call.Path != "/1/users" || session.SessionID != 42 || session.BatchSeq != i+1 || session.EstimatedNumTotal != float64(len(c.rows)) {
+				t.Fatalf("unexpected call. [case:%v] [i:%v] [call:%+v]", c.name, i, call)
+			}
+
+			if session.LastBatchFlag != (i == len(calls)-1) {
+				t.Fatalf("only the last batch should be flagged. [case:%v] [i:%v] [call:%+v]", c.name, i, call)
+			}
+		}
+
+		if fmt.Sprint(sizes) != c.batches {
+			t.Fatalf("unexpected batches. [case:%v] [expected:%v] [actual:%v]", c.name, c.batches, sizes)
+		}
+
+		if report.NumBatches != len(calls) || report.NumReceived != received || report.NumRejected != len(c.rows)-received {
+			t.Fatalf("unexpected report. [case:%v] [report:%+v]", c.name, report)
+		}
+
+		if report.SessionId != "42" {
+			t.Fatalf("report should tell the session. [case:%v] [session:%v]", c.name, report.SessionId)
+		}
+	}
+}
+
+func TestUploadUsersOperation(t *testing.T) {
+	cases := map[AudienceOperation]string{
+		AddUsersOperation:     "/1/users ",
+		ReplaceUsersOperation: "/1/usersreplace ",
+		RemoveUsersOperation:  "/1/users DELETE",
+	}
+
+	for op, expected := range cases {
+		var calls []audienceUploadCall
+		srv := newAudienceUploadTestServer(t, &calls)
+		client := newTestClient(srv)
+
+		_, err := client.UploadUsers(context.Background(), "1", []AudienceSchemaKey{SchemaEmail}, audienceRows("a@x.io"), AudienceUploadOptions{Operation: op})
+
+		if err != nil || len(calls) != 1 || calls[0].Path+" "+calls[0].Method != expected {
+			t.Fatalf("unexpected call. [op:%v] [expected:%v] [calls:%+v] [e:%v]", op, expected, calls, err)
+		}
+	}
+
+	client := &Client{session: &internal.Session{}}
+
+	if _, err := client.UploadUsers(context.Background(), "1", []AudienceSchemaKey{SchemaEmail}, audienceRows(), AudienceUploadOptions{Operation: "merge"}); err == nil {
+		t.Fatalf("unknown operation should fail.")
+	}
+}
+
+func TestUploadUsersRowErrors(t *testing.T) {
+	var calls []audienceUploadCall
+	srv := newAudienceUploadTestServer(t, &calls)
+	client := newTestClient(srv)
+
+	readErr := errors.New("read failure")
+	rows := func(yield func([]string, error) bool) {
+		_ = yield([]string{"a@x.io"}, nil) &&
+			yield(nil, &AudienceRowError{Line: 3, Err: errors.New("malformed")}) &&
+			yield(nil, readErr)
+	}
+
+	report, err := client.UploadUsers(context.Background(), "1", []AudienceSchemaKey{SchemaEmail}, rows, AudienceUploadOptions{})
+
+	if !errors.Is(err, readErr) {
+		t.Fatalf("other errors should stop the upload. [e:%v]", err)
+	}
+
+	if len(calls) != 0 || report.NumRejected != 1 || report.RejectedRows[0].Line != 3 {
+		t.Fatalf("row errors should be rejected without stopping. [calls:%v] [report:%+v]", calls, report)
+	}
+}
+
+func TestCSVRows(t *testing.T) {
+	const data = "EMAIL,FN\njohn@example.com,John\n\"broken,John\n"
+
+	var rows [][]string
+	var err error
+
+	for row, e := range CSVRows(strings.NewReader(data), true) {
+		if e != nil {
+			err = e
+			break
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) != 1 || !slices.Equal(rows[0], []string{"john@example.com", "John"}) {
+		t.Fatalf("header should be skipped. [rows:%v]", rows)
+	}
+
+	if err == nil {
+		t.Fatalf("malformed line should be reported.")
+	}
+
+	rows = nil
+
+	for row := range CSVRows(strings.NewReader("a@example.com\nb@example.com\n"), false) {
+		rows = append(rows, row)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("first line should be kept without header. [rows:%v]", rows)
+	}
+}
@@ -4,25 +4,110 @@ import (
 	"context"
 	"fmt"
 	"github.com/dreamdata-io/facebook/internal"
+	"iter"
 )
 
 type AudiencesAPI interface {
 	Audience(ctx context.Context, audienceID string, params Params) (Result, error)
+	AudienceTyped(ctx context.Context, audienceID string, params Params) (CustomAudienceInfo, error)
 	CustomAudiences(ctx context.Context, adAccountID string, params Params) (Result, error)
+	CustomAudiencesTyped(ctx context.Context, adAccountID string, params Params) ([]CustomAudienceInfo, error)
 	CreateAudience(ctx context.Context, adAccountID string, params Params) (Result, error)
+	CreateAudienceTyped(ctx context.Context, adAccountID string, request CreateAudienceRequest) (string, error)
+	UpdateAudience(ctx context.Context, audienceID string, params Params) (Result, error)
+	UpdateAudienceTyped(ctx context.Context, audienceID string, request UpdateAudienceRequest) error
+	DeleteAudience(ctx context.Context, audienceID string) error
+	CreateLookalikeAudience(ctx context.Context, adAccountID string, request CreateLookalikeRequest) (string, error)
+	LookalikeAudiences(ctx context.Context, seedAudienceID string) ([]CustomAudienceInfo, error)
+	AudienceAdAccounts(ctx context.Context, audienceID string) ([]string, error)
+	AudienceShares(ctx context.Context, audienceID string) ([]AudienceShare, error)
+	ShareAudience(ctx context.Context, audienceID string, adAccountIDs []string, opts AudienceShareOptions) error
+	UnshareAudience(ctx context.Context, audienceID string, adAccountIDs []string) error
+	BusinessAdAccounts(ctx context.Context, businessID string) ([]AdAccount, error)
+	ShareAudienceWithBusiness(ctx context.Context, audienceID string, businessID string, opts AudienceShareOptions) error
+	UnshareAudienceWithBusiness(ctx context.Context, audienceID string, businessID string) error
+	TOSAccepted(ctx context.Context, adAccountID string) (map[TermsOfService]bool, error)
+	BusinessTOSAccepted(ctx context.Context, businessID string) (map[TermsOfService]bool, error)
+	CustomAudienceTOSAccepted(ctx context.Context, adAccountID string) (bool, error)
 	AddUsers(ctx context.Context, audienceID string, payload AddUserPayload, session AddUserSession, params Params) (Result, error)
 	ReplaceUsers(ctx context.Context, audienceID string, payload AddUserPayload, session AddUserSession, params Params) (Result, error)
+	RemoveUsers(ctx context.Context, audienceID string, payload AddUserPayload, session AddUserSession, params Params) (Result, error)
 	Sessions(ctx context.Context, audienceID string, sessionID string, params Params) (Result, error)
+	AudienceSession(ctx context.Context, audienceID string, sessionID string) (AudienceSession, error)
+	WaitForSession(ctx context.Context, audienceID string, sessionID string, opts PollOptions) (AudienceSession, error)
+	UploadUsers(ctx context.Context, audienceID string, schema []AudienceSchemaKey, rows iter.Seq2[[]string, error], opts AudienceUploadOptions) (*AudienceUploadReport, error)
+	UploadCustomerFile(ctx context.Context, audienceID string, file *CustomerFile, opts AudienceUploadOptions) (*AudienceUploadReport, error)
 }
 
 // Audience calls the Facebook Graph API with GET at /{audience_id} to get an audience.
-func (c *Client) Audience(_ context.Context, audienceID string, params Params) (Result, error) {
-	return c.session.Get(fmt.Sprintf("/%s", audienceID), params)
+func (c *Client) Audience(ctx context.Context, audienceID string, params Params) (Result, error) {
+	return c.session.WithContext(ctx).Get(fmt.Sprintf("/%s", audienceID), params)
 }
 
 // CustomAudiences calls the Facebook Graph API with GET at /act_{ad_account_id}/customaudiences to get all audiences.
-func (c *Client) CustomAudiences(_ context.Context, adAccountID string, params Params) (Result, error) {
-	return c.session.Get(fmt.Sprintf("/act_%s/customaudiences", adAccountID), internal.MakeParams(params))
+func (c *Client) CustomAudiences(ctx context.Context, adAccountID string, params Params) (Result, error) {
+	return c.session.WithContext(ctx).Get(fmt.Sprintf("/act_%s/customaudiences", adAccountID), internal.MakeParams(params))
+}
+
+// CustomAudienceInfo is an audience of an ad account.
+// See https://developers.facebook.com/docs/marketing-api/reference/custom-audience.
+type CustomAudienceInfo struct {
+	ID                         string          `facebook:"id,required" json:"id"`
+	AccountID                  string          `json:"account_id"`
+	Name                       string          `json:"name"`
+	Description                string          `json:"description"`
+	Subtype                    AudienceSubtype `json:"subtype"`
+	ApproximateCountLowerBound int64           `json:"approximate_count_lower_bound"`
+	ApproximateCountUpperBound int64           `json:"approximate_count_upper_bound"`
+	OperationStatus            AudienceStatus  `json:"operation_status"`
+	DeliveryStatus             AudienceStatus  `json:"delivery_status"`
+	DataSource                 AudienceSource  `json:"data_source"`
+	RetentionDays              int             `json:"retention_days"`
+	CustomerFileSource         FileSource      `json:"customer_file_source"`
+	TimeCreated                int64           `json:"time_created"`           // unix timestamp.
+	TimeUpdated                int64           `json:"time_updated"`           // unix timestamp.
+	LookalikeSpec              *LookalikeSpec  `json:"lookalike_spec"`         // only set on lookalike audiences.
+	LookalikeAudienceIDs       []string        `json:"lookalike_audience_ids"` // lookalikes using this audience as seed.
+}
+
+// Populated reports whether facebook finished populating the audience, i.e. its operation status is "Normal".
+func (a CustomAudienceInfo) Populated() bool {
+	return a.OperationStatus.Code == 200
+}
+
+// AudienceStatus is the operation or delivery status of an audience.
+type AudienceStatus struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// AudienceSource describes where the users of an audience come from.
+type AudienceSource struct {
+	Type           string `json:"type"`
+	SubType        string `json:"sub_type"`
+	CreationParams string `json:"creation_params"`
+}
+
+var customAudienceFields = []string{"id", "account_id", "name", "description", "subtype",
+	"approximate_count_lower_bound", "approximate_count_upper_bound", "operation_status", "delivery_status",
+	"data_source", "retention_days", "customer_file_source", "time_created", "time_updated",
+	"lookalike_spec", "lookalike_audience_ids"}
+
+// AudienceTyped is Audience decoded into a CustomAudienceInfo.
+// Fields of CustomAudienceInfo are requested unless params selects fields.
+func (c *Client) AudienceTyped(ctx context.Context, audienceID string, params Params) (CustomAudienceInfo, error) {
+	res, err := c.Audience(ctx, audienceID, withDefaultFields(params, customAudienceFields...))
+	if err != nil {
+		return CustomAudienceInfo{}, err
+	}
+
+	return DecodeAs[CustomAudienceInfo](res)
+}
+
+// CustomAudiencesTyped is CustomAudiences decoded into CustomAudienceInfo values.
+// Only the first page is returned, use Paging and All to read all audiences.
+func (c *Client) CustomAudiencesTyped(ctx context.Context, adAccountID string, params Params) ([]CustomAudienceInfo, error) {
+	return decodeData[CustomAudienceInfo](c.CustomAudiences(ctx, adAccountID, withDefaultFields(params, customAudienceFields...)))
 }
 
 type AudienceSubtype = string
@@ -30,7 +115,8 @@ type AudienceSubtype = string
 type FileSource = string
 
 const (
-	CustomAudience AudienceSubtype = "CUSTOM"
+	CustomAudience           AudienceSubtype = "CUSTOM"
+	LookalikeAudienceSubtype AudienceSubtype = "LOOKALIKE"
 
 	UserProvidedOnlyFileSource     FileSource = "USER_PROVIDED_ONLY"
 	PartnerProvidedOnlyFileSource  FileSource = "PARTNER_PROVIDED_ONLY"
@@ -38,8 +124,133 @@ const (
 )
 
 // CreateAudience calls the Facebook API with POST at /act_{ad_account_id}/customaudiences to create a new audience.
+// It returns a *TOSNotAcceptedError if the Custom Audience terms are not accepted, like all methods changing audiences.
 func (c *Client) CreateAudience(ctx context.Context, adAccountID string, params Params) (Result, error) {
-	return c.session.Post(fmt.Sprintf("/act_%s/customaudiences", adAccountID), params)
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/act_%s/customaudiences", adAccountID), params)
+	return res, checkTOS(err, adAccountID)
+}
+
+// CreateAudienceRequest holds the fields of a new audience.
+type CreateAudienceRequest struct {
+	Name               string
+	Description        string
+	Subtype            AudienceSubtype // defaults to CustomAudience, unless Rule is set.
+	CustomerFileSource FileSource      // required for customer file audiences.
+	RetentionDays      int             // zero keeps facebook's default.
+	Rule               *AudienceRule   // rule of website and engagement audiences, validated before sending.
+	Params             Params          // extra fields, e.g. lookalike_spec or prefill.
+}
+
+func (r CreateAudienceRequest) params() (Params, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("facebook: audience name is empty")
+	}
+
+	p := make(Params, len(r.Params)+6)
+	for k, v := range r.Params {
+		p[k] = v
+	}
+
+	p["name"] = r.Name
+
+	if r.Subtype != "" {
+		p["subtype"] = r.Subtype
+	} else if r.Rule == nil {
+		p["subtype"] = CustomAudience
+	}
+
+	if r.Rule != nil {
+		if err := r.Rule.Validate(); err != nil {
+			return nil, err
+		}
+
+		p["rule"] = r.Rule
+	}
+
+	if r.Description != "" {
+		p["description"] = r.Description
+	}
+
+	if r.CustomerFileSource != "" {
+		p["customer_file_source"] = r.CustomerFileSource
+	}
+
+	if r.RetentionDays > 0 {
+		p["retention_days"] = r.RetentionDays
+	}
+
+	return p, nil
+}
+
+// CreateAudienceTyped is CreateAudience with the fields of request. It returns the ID of the new audience.
+func (c *Client) CreateAudienceTyped(ctx context.Context, adAccountID string, request CreateAudienceRequest) (string, error) {
+	params, err := request.params()
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.CreateAudience(ctx, adAccountID, params)
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeFieldAs[string](res, "id")
+}
+
+// UpdateAudience calls the Facebook API with POST at /{audience_id} to update an audience.
+func (c *Client) UpdateAudience(ctx context.Context, audienceID string, params Params) (Result, error) {
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/%s", audienceID), params)
+	return res, checkTOS(err, "")
+}
+
+// UpdateAudienceRequest holds the fields of an audience to update. Empty fields are left unchanged.
+type UpdateAudienceRequest struct {
+	Name          string
+	Description   string
+	RetentionDays int
+	Rule          *AudienceRule // validated before sending.
+	Params        Params        // extra fields.
+}
+
+// UpdateAudienceTyped is UpdateAudience with the fields of request.
+func (c *Client) UpdateAudienceTyped(ctx context.Context, audienceID string, request UpdateAudienceRequest) error {
+	p := make(Params, len(request.Params)+4)
+	for k, v := range request.Params {
+		p[k] = v
+	}
+
+	if request.Name != "" {
+		p["name"] = request.Name
+	}
+
+	if request.Description != "" {
+		p["description"] = request.Description
+	}
+
+	if request.RetentionDays > 0 {
+		p["retention_days"] = request.RetentionDays
+	}
+
+	if request.Rule != nil {
+		if err := request.Rule.Validate(); err != nil {
+			return err
+		}
+
+		p["rule"] = request.Rule
+	}
+
+	if len(p) == 0 {
+		return fmt.Errorf("facebook: no audience field to update")
+	}
+
+	_, err := c.UpdateAudience(ctx, audienceID, p)
+	return err
+}
+
+// DeleteAudience calls the Facebook API with DELETE at /{audience_id} to delete an audience.
+func (c *Client) DeleteAudience(ctx context.Context, audienceID string) error {
+	_, err := c.session.WithContext(ctx).Delete(fmt.Sprintf("/%s", audienceID), nil)
+	return err
 }
 
 type AddUserSession struct {
@@ -85,7 +296,8 @@ func (c *Client) AddUsers(ctx context.Context, audienceID string, payload AddUse
 	}
 	params["payload"] = payload.Format()
 	params["session"] = session.Format()
-	return c.session.Post(fmt.Sprintf("/%s/users", audienceID), params)
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/%s/users", audienceID), params)
+	return res, checkTOS(err, "")
 }
 
 // ReplaceUsers calls the Facebook API with POST at /{audience_id}/usersreplace to replace users in an audience.
@@ -95,7 +307,19 @@ func (c *Client) ReplaceUsers(ctx context.Context, audienceID string, payload Ad
 	}
 	params["payload"] = payload.Format()
 	params["session"] = session.Format()
-	return c.session.Post(fmt.Sprintf("/%s/usersreplace", audienceID), params)
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/%s/usersreplace", audienceID), params)
+	return res, checkTOS(err, "")
+}
+
+// RemoveUsers calls the Facebook API with DELETE at /{audience_id}/users to remove users from an audience.
+func (c *Client) RemoveUsers(ctx context.Context, audienceID string, payload AddUserPayload, session AddUserSession, params Params) (Result, error) {
+	if params == nil {
+		params = make(Params)
+	}
+	params["payload"] = payload.Format()
+	params["session"] = session.Format()
+	res, err := c.session.WithContext(ctx).Delete(fmt.Sprintf("/%s/users", audienceID), params)
+	return res, checkTOS(err, "")
 }
 
 // Sessions calls the Facebook API with GET at /{audience_id}/sessions to get information on audience operation sessions.
@@ -104,5 +328,5 @@ func (c *Client) Sessions(ctx context.Context, audienceID string, sessionID stri
 		params = make(Params)
 	}
 	params["session_id"] = sessionID
-	return c.session.Get(fmt.Sprintf("/%s/sessions", audienceID), params)
+	return c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/sessions", audienceID), params)
 }
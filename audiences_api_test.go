@@ -0,0 +1,102 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateAudienceRequestParams(t *testing.T) {
+	rule := AudienceRule{}.Include(NewAudienceRuleEntry(24*time.Hour, AudienceRuleEventSource{ID: "123", Type: PixelEventSource}))
+	invalid := AudienceRule{}.Include(NewAudienceRuleEntry(time.Hour))
+
+	cases := []struct {
+		name    string
+		request CreateAudienceRequest
+		params  string // params without the rule, empty if the request is invalid.
+	}{
+		{
+			name:    "customer file",
+			request: CreateAudienceRequest{Name: "a", CustomerFileSource: UserProvidedOnlyFileSource, RetentionDays: 30},
+			params:  "map[customer_file_source:USER_PROVIDED_ONLY name:a retention_days:30 subtype:CUSTOM]",
+		},
+		{
+			name:    "rule",
+			request: CreateAudienceRequest{Name: "a", Description: "d", Rule: &rule},
+			params:  "map[description:d name:a]",
+		},
+		{
+			name:    "rule with subtype",
+			request: CreateAudienceRequest{Name: "a", Subtype: WebsiteAudienceSubtype, Rule: &rule},
+			params:  "map[name:a subtype:WEBSITE]",
+		},
+		{
+			name:    "extra params",
+			request: CreateAudienceRequest{Name: "a", Params: Params{"prefill": true, "name": "b"}},
+			params:  "map[name:a prefill:true subtype:CUSTOM]",
+		},
+		{name: "no name", request: CreateAudienceRequest{Rule: &rule}},
+		{name: "invalid rule", request: CreateAudienceRequest{Name: "a", Rule: &invalid}},
+	}
+
+	for _, c := range cases {
+		params, err := c.request.params()
+
+		if c.params == "" {
+			if err == nil {
+				t.Fatalf("invalid request should be rejected. [case:%v] [params:%v]", c.name, params)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("cannot create params. [case:%v] [e:%v]", c.name, err)
+		}
+
+		if rule, ok := params["rule"]; ok != (c.request.Rule != nil) || ok && rule != c.request.Rule {
+			t.Fatalf("rule should be sent as is. [case:%v] [rule:%v]", c.name, params["rule"])
+		}
+
+		delete(params, "rule")
+
+		if fmt.Sprint(params) != c.params {
+			t.Fatalf("unexpected params. [case:%v] [expected:%v] [actual:%v]", c.name, c.params, params)
+		}
+	}
+}
+
+func TestUpdateAudienceTyped(t *testing.T) {
+	var updates []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		updates = append(updates, r.URL.Path+" "+fmt.Sprint(r.PostForm))
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	ctx := context.Background()
+	invalid := AudienceRule{}
+
+	if err := client.UpdateAudienceTyped(ctx, "1", UpdateAudienceRequest{}); err == nil {
+		t.Fatalf("empty update should be rejected.")
+	}
+
+	if err := client.UpdateAudienceTyped(ctx, "1", UpdateAudienceRequest{Name: "a", Rule: &invalid}); err == nil {
+		t.Fatalf("update with an invalid rule should be rejected.")
+	}
+
+	if err := client.UpdateAudienceTyped(ctx, "1", UpdateAudienceRequest{RetentionDays: 30, Params: Params{"opt_out_link": "x"}}); err != nil {
+		t.Fatalf("update should succeed. [e:%v]", err)
+	}
+
+	if fmt.Sprint(updates) != "[/1 map[format:[json] opt_out_link:[x] retention_days:[30]]]" {
+		t.Fatalf("only valid updates should be sent. [updates:%v]", updates)
+	}
+}
@@ -35,12 +35,18 @@ func (c *Client) Auth(ctx context.Context, token *oauth2.Token, opts ...AuthOpti
 	session := c.app.Session("")
 	session.Version = c.version
 	session.HttpClient = cfg.Client(ctx, token)
+	session.RetryPolicy = c.retryPolicy
+	session.Throttler = c.throttler
 
 	return &Client{
-		app:          c.app,
-		oauth2Config: cfg,
-		session:      session,
-		version:      c.version,
+		app:           c.app,
+		oauth2Config:  cfg,
+		session:       session,
+		version:       c.version,
+		retryPolicy:   c.retryPolicy,
+		throttler:     c.throttler,
+		testEventCode: c.testEventCode,
+		deduplicator:  c.deduplicator,
 	}
 }
 
@@ -73,7 +79,7 @@ func (c *Client) AccessToken(ctx context.Context, refreshToken string) (*oauth2.
 	return t, nil
 }
 
-func (c *Client) RevokeAccessToken(_ context.Context, accessToken string) error {
+func (c *Client) RevokeAccessToken(ctx context.Context, accessToken string) error {
 	params := map[string]string{
 		"client_id":     c.oauth2Config.ClientID,
 		"client_secret": c.oauth2Config.ClientSecret,
@@ -81,7 +87,7 @@ func (c *Client) RevokeAccessToken(_ context.Context, accessToken string) error
 		"access_token":  accessToken,
 	}
 
-	res, err := c.session.Get("/oauth/revoke", internal.MakeParams(params))
+	res, err := c.session.WithContext(ctx).Get("/oauth/revoke", internal.MakeParams(params))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,290 @@
+package facebook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dreamdata-io/facebook/internal"
+	"sort"
+	"strings"
+)
+
+// MaxBatchSize is the maximum number of requests facebook accepts in a single batch call.
+const MaxBatchSize = 50
+
+type BatchResult = internal.BatchResult
+type BinaryData = internal.BinaryData
+type BinaryFile = internal.BinaryFile
+
+type BatchAPI interface {
+	NewBatch() *BatchBuilder
+	Batch(ctx context.Context, requests ...BatchRequest) ([]BatchResponse, error)
+}
+
+// BatchRequest is a single Graph API call in a batch.
+// See https://developers.facebook.com/docs/graph-api/batch-requests.
+type BatchRequest struct {
+	Method      Method
+	RelativeURL string
+	Body        Params // sent as form data, or as query string for GET.
+	Name        string // name referenced by DependsOn and JSONPath expressions of other requests.
+	DependsOn   string // name of a request which must succeed first.
+
+	// OmitResponseOnSuccess controls whether facebook returns the response of a named request.
+	// nil keeps facebook's default, which omits responses of requests other requests depend on.
+	OmitResponseOnSuccess *bool
+
+	// AttachedFiles maps attachment names to *BinaryData or *BinaryFile.
+	AttachedFiles map[string]interface{}
+}
+
+// BatchResponse is the response to a single BatchRequest.
+type BatchResponse struct {
+	Request BatchRequest
+	Result  *BatchResult // nil if facebook omits the response or the call fails.
+	Err     error        // *Error returned by facebook for this request, or the error of the whole batch call.
+	Skipped bool         // true if the request didn't run as a request it depends on failed. Err is a *BatchDependencyError.
+}
+
+// BatchBuilder collects requests sent with a single Client.Batch call.
+type BatchBuilder struct {
+	client   *Client
+	requests []BatchRequest
+}
+
+// NewBatch creates a BatchBuilder sending requests with c.
+func (c *Client) NewBatch() *BatchBuilder {
+	return &BatchBuilder{client: c}
+}
+
+// Add adds requests to the batch.
+func (b *BatchBuilder) Add(requests ...BatchRequest) *BatchBuilder {
+	b.requests = append(b.requests, requests...)
+	return b
+}
+
+// Get adds a GET request to the batch.
+func (b *BatchBuilder) Get(relativeURL string, params Params) *BatchBuilder {
+	return b.Add(BatchRequest{Method: internal.GET, RelativeURL: relativeURL, Body: params})
+}
+
+// Post adds a POST request to the batch.
+func (b *BatchBuilder) Post(relativeURL string, params Params) *BatchBuilder {
+	return b.Add(BatchRequest{Method: internal.POST, RelativeURL: relativeURL, Body: params})
+}
+
+// Delete adds a DELETE request to the batch.
+func (b *BatchBuilder) Delete(relativeURL string, params Params) *BatchBuilder {
+	return b.Add(BatchRequest{Method: internal.DELETE, RelativeURL: relativeURL, Body: params})
+}
+
+// Requests returns all requests added to the batch.
+func (b *BatchBuilder) Requests() []BatchRequest {
+	return b.requests
+}
+
+// Validate checks the batch without sending it. It reports duplicated names, references to unknown requests,
+// dependency cycles and dependent requests which cannot be sent in a single call.
+func (b *BatchBuilder) Validate() error {
+	_, err := planBatch(b.requests)
+	return err
+}
+
+// Send sends all requests with Client.Batch.
+func (b *BatchBuilder) Send(ctx context.Context) ([]BatchResponse, error) {
+	return b.client.Batch(ctx, b.requests...)
+}
+
+// Batch calls the Facebook Graph API with a batch request.
+//
+// Requests are sent after the requests they depend on, split into calls of at most MaxBatchSize requests
+// keeping every request in the same call as the requests it depends on. See BatchBuilder.Validate for
+// the checks done before sending.
+//
+// There is one response per request, in the same order. If a whole call fails, the error is set on
+// the responses of its requests and the first such error is returned.
+func (c *Client) Batch(ctx context.Context, requests ...BatchRequest) ([]BatchResponse, error) {
+	plan, err := planBatch(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	session := c.session.WithContext(ctx)
+	responses := make([]BatchResponse, len(requests))
+	callFailed := make([]bool, len(requests))
+	var batchErr error
+
+	for _, chunk := range plan.chunks {
+		batch := make([]BatchRequest, len(chunk))
+		for k, i := range chunk {
+			batch[k] = requests[i]
+		}
+
+		batchParams, params, err := encodeBatch(batch)
+
+		var results []Result
+		if err == nil {
+			results, err = session.Batch(batchParams, params...)
+		}
+
+		if err == nil && len(results) != len(chunk) {
+			err = fmt.Errorf("facebook: batch returns %d results for %d requests", len(results), len(chunk))
+		}
+
+		if err != nil {
+			if batchErr == nil {
+				batchErr = err
+			}
+
+			for _, i := range chunk {
+				responses[i] = BatchResponse{Request: requests[i], Err: err}
+				callFailed[i] = true
+			}
+
+			continue
+		}
+
+		for k, i := range chunk {
+			responses[i] = newBatchResponse(requests[i], results[k])
+		}
+	}
+
+	plan.markSkipped(responses, callFailed)
+	return responses, batchErr
+}
+
+func newBatchResponse(request BatchRequest, res Result) BatchResponse {
+	response := BatchResponse{Request: request}
+
+	// facebook returns null for omitted responses.
+	if res == nil {
+		return response
+	}
+
+	result, err := res.Batch()
+	if err != nil {
+		response.Err = err
+		return response
+	}
+
+	response.Result = result
+
+	if result.Result != nil {
+		response.Err = result.Result.Err()
+	}
+
+	return response
+}
+
+// splitBatch splits order into chunks of at most MaxBatchSize requests.
+// reach holds, for every position in order, the furthest position of a request depending on it.
+// A chunk is only cut where no later request depends on a request before the cut.
+func splitBatch(order []int, reach []int) ([][]int, error) {
+	var chunks [][]int
+
+	for start := 0; start < len(order); {
+		end, furthest, cut := start, start, -1
+
+		for end < len(order) && end-start < MaxBatchSize {
+			furthest = max(furthest, reach[end])
+			end++
+
+			if furthest < end {
+				cut = end
+			}
+		}
+
+		if end < len(order) {
+			if cut < 0 {
+				return nil, errors.New("facebook: dependent batch requests do not fit in a single batch call")
+			}
+
+			end = cut
+		}
+
+		chunks = append(chunks, order[start:end])
+		start = end
+	}
+
+	return chunks, nil
+}
+
+// encodeBatch encodes requests into the params of Session.Batch.
+func encodeBatch(requests []BatchRequest) (Params, []Params, error) {
+	batchParams := Params{}
+	params := make([]Params, 0, len(requests))
+
+	for _, request := range requests {
+		p, err := request.params()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for name, file := range request.AttachedFiles {
+			if _, ok := batchParams[name]; ok {
+				return nil, nil, fmt.Errorf("facebook: duplicated batch attachment name %q", name)
+			}
+
+			batchParams[name] = file
+		}
+
+		params = append(params, p)
+	}
+
+	return batchParams, params, nil
+}
+
+func (r BatchRequest) params() (Params, error) {
+	method := r.Method
+	if method == "" {
+		method = internal.GET
+	}
+
+	relativeURL := r.RelativeURL
+	p := Params{"method": method}
+
+	if len(r.Body) > 0 {
+		buf := &bytes.Buffer{}
+		if _, err := r.Body.Encode(buf); err != nil {
+			return nil, fmt.Errorf("facebook: cannot encode batch request body; %w", err)
+		}
+
+		if method == internal.GET {
+			sep := "?"
+			if strings.Contains(relativeURL, "?") {
+				sep = "&"
+			}
+
+			relativeURL += sep + buf.String()
+		} else {
+			p["body"] = buf.String()
+		}
+	}
+
+	p["relative_url"] = relativeURL
+
+	if r.Name != "" {
+		p["name"] = r.Name
+	}
+
+	if r.DependsOn != "" {
+		p["depends_on"] = r.DependsOn
+	}
+
+	if r.OmitResponseOnSuccess != nil {
+		p["omit_response_on_success"] = *r.OmitResponseOnSuccess
+	}
+
+	if len(r.AttachedFiles) > 0 {
+		files := make([]string, 0, len(r.AttachedFiles))
+		for name := range r.AttachedFiles {
+			files = append(files, name)
+		}
+
+		sort.Strings(files)
+		p["attached_files"] = strings.Join(files, ",")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,227 @@
+package facebook
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// batchReferencePattern matches JSONPath references like {result=name:$.data.*.id}.
+var batchReferencePattern = regexp.MustCompile(`\{result=([^:}]+):`)
+
+// BatchRef refers to a named request in a batch.
+// It's created by BatchBuilder.Named so that references always point to a request in the batch.
+type BatchRef struct {
+	name string
+}
+
+// Name returns the name of the referenced request.
+func (r BatchRef) Name() string {
+	return r.name
+}
+
+// Result returns a JSONPath expression referencing the response of the request, e.g.
+// ref.Result("$.data.*.id") returns "{result=name:$.data.*.id}".
+// Use it in RelativeURL or Body of requests in the same batch.
+func (r BatchRef) Result(jsonPath string) string {
+	return fmt.Sprintf("{result=%s:%s}", r.name, jsonPath)
+}
+
+// BatchDependencyError is set on the responses of requests skipped because a request they depend on,
+// directly or indirectly, failed.
+type BatchDependencyError struct {
+	Name string // name of the failed request.
+	Err  error  // error of the failed request.
+}
+
+func (e *BatchDependencyError) Error() string {
+	return fmt.Sprintf("facebook: batch request %q this request depends on failed; %v", e.Name, e.Err)
+}
+
+func (e *BatchDependencyError) Unwrap() error {
+	return e.Err
+}
+
+// Named adds request to the batch with name and returns a reference to it.
+func (b *BatchBuilder) Named(name string, request BatchRequest) BatchRef {
+	request.Name = name
+	b.Add(request)
+	return BatchRef{name: name}
+}
+
+// After adds request to the batch so that it only runs once parent succeeded.
+func (b *BatchBuilder) After(parent BatchRef, request BatchRequest) *BatchBuilder {
+	request.DependsOn = parent.name
+	return b.Add(request)
+}
+
+// dependencies returns names of requests r depends on, either with DependsOn or JSONPath references.
+func (r BatchRequest) dependencies() []string {
+	var names []string
+
+	scan := func(s string) {
+		for _, match := range batchReferencePattern.FindAllStringSubmatch(s, -1) {
+			names = append(names, match[1])
+		}
+	}
+
+	if r.DependsOn != "" {
+		names = append(names, r.DependsOn)
+	}
+
+	scan(r.RelativeURL)
+
+	for _, v := range r.Body {
+		if s, ok := v.(string); ok {
+			scan(s)
+		} else {
+			scan(fmt.Sprint(v))
+		}
+	}
+
+	return names
+}
+
+type batchPlan struct {
+	order   []int   // request indexes with every request after the requests it depends on.
+	parents [][]int // indexes of requests each request depends on.
+	chunks  [][]int // request indexes sent in each call.
+}
+
+// planBatch validates dependencies between requests and decides the calls to send.
+func planBatch(requests []BatchRequest) (*batchPlan, error) {
+	names := make(map[string]int, len(requests))
+
+	for i, request := range requests {
+		if request.Name == "" {
+			continue
+		}
+
+		if _, ok := names[request.Name]; ok {
+			return nil, fmt.Errorf("facebook: duplicated batch request name %q", request.Name)
+		}
+
+		names[request.Name] = i
+	}
+
+	plan := &batchPlan{
+		parents: make([][]int, len(requests)),
+	}
+	children := make([][]int, len(requests))
+	pending := make([]int, len(requests))
+
+	for i, request := range requests {
+		seen := map[int]bool{}
+
+		for _, name := range request.dependencies() {
+			parent, ok := names[name]
+			if !ok {
+				return nil, fmt.Errorf("facebook: batch request %d depends on unknown request %q", i, name)
+			}
+
+			if seen[parent] {
+				continue
+			}
+
+			seen[parent] = true
+			plan.parents[i] = append(plan.parents[i], parent)
+			children[parent] = append(children[parent], i)
+			pending[i]++
+		}
+	}
+
+	// topological sort which keeps the original order as much as possible.
+	var ready []int
+
+	for i := range requests {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		plan.order = append(plan.order, i)
+
+		for _, child := range children[i] {
+			pending[child]--
+
+			if pending[child] == 0 {
+				at := sort.SearchInts(ready, child)
+				ready = append(ready, 0)
+				copy(ready[at+1:], ready[at:])
+				ready[at] = child
+			}
+		}
+	}
+
+	if len(plan.order) < len(requests) {
+		var cycle []string
+
+		for i, request := range requests {
+			if pending[i] > 0 && request.Name != "" {
+				cycle = append(cycle, fmt.Sprintf("%q", request.Name))
+			}
+		}
+
+		return nil, fmt.Errorf("facebook: batch requests have cyclic dependencies among %s", strings.Join(cycle, ", "))
+	}
+
+	position := make([]int, len(requests))
+
+	for pos, i := range plan.order {
+		position[i] = pos
+	}
+
+	reach := make([]int, len(requests))
+
+	for pos := range reach {
+		reach[pos] = pos
+	}
+
+	for i, parents := range plan.parents {
+		for _, parent := range parents {
+			reach[position[parent]] = max(reach[position[parent]], position[i])
+		}
+	}
+
+	chunks, err := splitBatch(plan.order, reach)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.chunks = chunks
+	return plan, nil
+}
+
+// markSkipped marks responses of requests which depend on a failed request.
+// Requests of a failed call are not marked as they are not run at all.
+func (plan *batchPlan) markSkipped(responses []BatchResponse, callFailed []bool) {
+	for _, i := range plan.order {
+		if callFailed[i] {
+			continue
+		}
+
+		for _, parent := range plan.parents[i] {
+			if responses[parent].Err == nil {
+				continue
+			}
+
+			responses[i].Skipped = true
+
+			// report the request which actually failed if the parent is skipped too.
+			if responses[parent].Skipped {
+				responses[i].Err = responses[parent].Err
+			} else {
+				responses[i].Err = &BatchDependencyError{
+					Name: responses[parent].Request.Name,
+					Err:  responses[parent].Err,
+				}
+			}
+
+			break
+		}
+	}
+}
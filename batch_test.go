@@ -0,0 +1,293 @@
+package facebook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newBatchTestServer answers batch calls with the relative URL of every request as id.
+// Requests whose relative URL starts with "fail" fail, and requests depending on a failed request
+// get null, like facebook does. The relative URLs of every call are appended to calls.
+func newBatchTestServer(t *testing.T, calls *[][]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]interface{}
+
+		if err := json.Unmarshal([]byte(r.FormValue("batch")), &batch); err != nil {
+			t.Errorf("invalid batch param. [e:%v]", err)
+		}
+
+		var urls, results []string
+		failed := map[string]bool{}
+
+		for _, request := range batch {
+			url, _ := request["relative_url"].(string)
+			name, _ := request["name"].(string)
+			parent, _ := request["depends_on"].(string)
+			urls = append(urls, url)
+
+			switch {
+			case parent != "" && failed[parent]:
+				failed[name] = true
+				results = append(results, "null")
+			case strings.HasPrefix(url, "fail"):
+				failed[name] = true
+				results = append(results, `{"code": 400, "headers": [], "body": "{\"error\": {\"message\": \"failed\", \"code\": 100}}"}`)
+			default:
+				results = append(results, fmt.Sprintf(`{"code": 200, "headers": [], "body": "{\"id\": \"%s\"}"}`, url))
+			}
+		}
+
+		*calls = append(*calls, urls)
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, "[%s]", strings.Join(results, ","))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// batchRequests creates n independent GET requests with relative URLs "0", "1"...
+func batchRequests(n int) []BatchRequest {
+	requests := make([]BatchRequest, n)
+
+	for i := range requests {
+		requests[i] = BatchRequest{RelativeURL: fmt.Sprint(i)}
+	}
+
+	return requests
+}
+
+func TestPlanBatchErrors(t *testing.T) {
+	chain := batchRequests(MaxBatchSize + 1)
+
+	for i := range chain {
+		chain[i].Name = fmt.Sprint("r", i)
+
+		if i > 0 {
+			chain[i].DependsOn = fmt.Sprint("r", i-1)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		requests []BatchRequest
+		err      string
+	}{
+		{
+			name: "cycle",
+			requests: []BatchRequest{
+				{RelativeURL: "me", Name: "a", DependsOn: "b"},
+				{RelativeURL: "{result=a:$.id}", Name: "b"},
+				{RelativeURL: "other"},
+			},
+			err: `cyclic dependencies among "a", "b"`,
+		},
+		{
+			name:     "self reference",
+			requests: []BatchRequest{{RelativeURL: "{result=a:$.id}", Name: "a"}},
+			err:      `cyclic dependencies among "a"`,
+		},
+		{
+			name:     "unknown DependsOn",
+			requests: []BatchRequest{{RelativeURL: "me", DependsOn: "missing"}},
+			err:      `batch request 0 depends on unknown request "missing"`,
+		},
+		{
+			name: "unknown JSONPath reference",
+			requests: []BatchRequest{
+				{RelativeURL: "me", Name: "a"},
+				{RelativeURL: "me/feed", Body: Params{"ids": "{result=b:$.data.*.id}"}},
+			},
+			err: `batch request 1 depends on unknown request "b"`,
+		},
+		{
+			name: "duplicated name",
+			requests: []BatchRequest{
+				{RelativeURL: "me", Name: "a"},
+				{RelativeURL: "me", Name: "a"},
+			},
+			err: `duplicated batch request name "a"`,
+		},
+		{
+			name:     "chain longer than a call",
+			requests: chain,
+			err:      "do not fit in a single batch call",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := planBatch(c.requests)
+
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Fatalf("unexpected error. [case:%v] [expected:%v] [actual:%v]", c.name, c.err, err)
+		}
+	}
+}
+
+func TestPlanBatch(t *testing.T) {
+	// the parent at 45 and its child at 55 must be in the same call, so the first call is cut before 45.
+	crossing := batchRequests(60)
+	crossing[45].Name = "parent"
+	crossing[55].DependsOn = "parent"
+
+	// the child depends on a parent after it only with a JSONPath reference.
+	reordered := []BatchRequest{
+		{RelativeURL: "child", Body: Params{"ids": "{result=parent:$.data.*.id}"}},
+		{RelativeURL: "other"},
+		{RelativeURL: "parent", Name: "parent"},
+	}
+
+	cases := []struct {
+		name     string
+		requests []BatchRequest
+		order    string
+		chunks   string // sizes of chunks.
+		parents  string
+	}{
+		{
+			name:     "JSONPath dependency",
+			requests: reordered,
+			order:    "[1 2 0]",
+			chunks:   "[3]",
+			parents:  "[[2] [] []]",
+		},
+		{
+			name:     "more than a call without dependencies",
+			requests: batchRequests(120),
+			chunks:   "[50 50 20]",
+		},
+		{
+			name:     "dependency crossing a call boundary",
+			requests: crossing,
+			chunks:   "[45 15]",
+		},
+		{
+			name: "dependency within a call",
+			requests: append(append(batchRequests(10), BatchRequest{RelativeURL: "parent", Name: "p"},
+				BatchRequest{RelativeURL: "child", DependsOn: "p"}), batchRequests(50)...),
+			chunks: "[50 12]",
+		},
+	}
+
+	for _, c := range cases {
+		plan, err := planBatch(c.requests)
+
+		if err != nil {
+			t.Fatalf("cannot plan batch. [case:%v] [e:%v]", c.name, err)
+		}
+
+		var sizes []int
+		for _, chunk := range plan.chunks {
+			sizes = append(sizes, len(chunk))
+		}
+
+		if fmt.Sprint(sizes) != c.chunks {
+			t.Fatalf("unexpected chunks. [case:%v] [expected:%v] [actual:%v]", c.name, c.chunks, sizes)
+		}
+
+		if c.order != "" && fmt.Sprint(plan.order) != c.order {
+			t.Fatalf("unexpected order. [case:%v] [expected:%v] [actual:%v]", c.name, c.order, plan.order)
+		}
+
+		if c.parents != "" && fmt.Sprint(plan.parents) != c.parents {
+			t.Fatalf("unexpected parents. [case:%v] [expected:%v] [actual:%v]", c.name, c.parents, plan.parents)
+		}
+	}
+}
+
+func TestBatchResponseOrder(t *testing.T) {
+	var calls [][]string
+	srv := newBatchTestServer(t, &calls)
+	client := newTestClient(srv)
+
+	requests := append([]BatchRequest{
+		{RelativeURL: "child", DependsOn: "parent"},
+		{RelativeURL: "parent", Name: "parent"},
+	}, batchRequests(MaxBatchSize)...)
+
+	responses, err := client.Batch(context.Background(), requests...)
+
+	if err != nil {
+		t.Fatalf("batch should succeed. [e:%v]", err)
+	}
+
+	if len(calls) != 2 || calls[0][0] != "parent" || calls[0][1] != "child" {
+		t.Fatalf("parent should be sent first. [calls:%v]", calls)
+	}
+
+	for i, response := range responses {
+		if response.Err != nil || response.Result == nil {
+			t.Fatalf("response should succeed. [i:%v] [response:%v]", i, response)
+		}
+
+		if id := response.Result.Result.Get("id"); id != requests[i].RelativeURL {
+			t.Fatalf("responses should be in the order of requests. [i:%v] [expected:%v] [actual:%v]", i, requests[i].RelativeURL, id)
+		}
+	}
+}
+
+func TestBatchSkippedGrandchild(t *testing.T) {
+	var calls [][]string
+	srv := newBatchTestServer(t, &calls)
+	client := newTestClient(srv)
+
+	batch := client.NewBatch()
+	parent := batch.Named("parent", BatchRequest{RelativeURL: "fail"})
+	child := batch.Named("child", BatchRequest{RelativeURL: "child", DependsOn: parent.Name()})
+	batch.Add(BatchRequest{RelativeURL: "grandchild", DependsOn: child.Name()})
+	batch.Get("other", nil)
+
+	responses, err := batch.Send(context.Background())
+
+	if err != nil {
+		t.Fatalf("batch call should succeed. [e:%v]", err)
+	}
+
+	var fbErr *Error
+
+	if !errors.As(responses[0].Err, &fbErr) || responses[0].Skipped {
+		t.Fatalf("parent should fail with a facebook error. [response:%v]", responses[0])
+	}
+
+	for _, response := range responses[1:3] {
+		var depErr *BatchDependencyError
+
+		if !response.Skipped || !errors.As(response.Err, &depErr) || depErr.Name != "parent" || !errors.Is(response.Err, fbErr) {
+			t.Fatalf("dependent request should be skipped because of parent. [response:%v]", response)
+		}
+	}
+
+	if responses[3].Err != nil || responses[3].Skipped {
+		t.Fatalf("independent request should succeed. [response:%v]", responses[3])
+	}
+}
+
+func TestBatchCallFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"message": "invalid token", "code": 190}}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	responses, err := client.Batch(context.Background(),
+		BatchRequest{RelativeURL: "me", Name: "a"},
+		BatchRequest{RelativeURL: "me/feed", DependsOn: "a"},
+	)
+
+	if err == nil {
+		t.Fatalf("batch call should fail.")
+	}
+
+	for _, response := range responses {
+		if response.Skipped || !errors.Is(response.Err, err) {
+			t.Fatalf("requests of a failed call should have its error without being skipped. [response:%v]", response)
+		}
+	}
+}
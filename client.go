@@ -10,26 +10,64 @@ import (
 type IClient interface {
 	Auth(context.Context, *oauth2.Token, ...AuthOption) IClient
 	Session() *internal.Session
+	Paging(context.Context, Result) (*PagingResult, error)
 	AuthClient
 	ConversionsAPI
 	MeAPI
 	AudiencesAPI
+	BatchAPI
+	AsyncBatchAPI
 }
 
 type Client struct {
-	oauth2Config *oauth2.Config
-	version      string
-	session      *internal.Session
-	app          *internal.App
+	oauth2Config  *oauth2.Config
+	version       string
+	retryPolicy   *RetryPolicy
+	throttler     *Throttler
+	testEventCode string
+	deduplicator  *Deduplicator
+	session       *internal.Session
+	app           *internal.App
+}
+
+type ClientOption func(*Client)
+
+// WithRetryPolicy retries Graph API calls failing with transient errors according to policy.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithThrottler delays calls of all sessions created by the client as rate limit usage approaches 100%.
+func WithThrottler(throttler *Throttler) ClientOption {
+	return func(c *Client) {
+		c.throttler = throttler
+	}
+}
+
+// WithTestEventCode sends all Conversions API events with test_event_code, so that facebook shows them
+// in the Test Events tool of Events Manager instead of using them for ads.
+func WithTestEventCode(code string) ClientOption {
+	return func(c *Client) {
+		c.testEventCode = code
+	}
+}
+
+// WithDeduplicator suppresses Conversions API events already sent by the client, see Deduplicator.
+func WithDeduplicator(d *Deduplicator) ClientOption {
+	return func(c *Client) {
+		c.deduplicator = d
+	}
 }
 
 var _ IClient = (*Client)(nil)
 
-func New(cfg Config) *Client {
+func New(cfg Config, opts ...ClientOption) *Client {
 	app := internal.New(cfg.OAuth2.ClientID, cfg.OAuth2.ClientSecret)
 	app.RedirectUri = cfg.OAuth2.RedirectURL
 
-	return &Client{
+	c := &Client{
 		version: cfg.Version,
 		oauth2Config: &oauth2.Config{
 			ClientID:     cfg.OAuth2.ClientID,
@@ -43,6 +81,12 @@ func New(cfg Config) *Client {
 		},
 		app: internal.New(cfg.OAuth2.ClientID, cfg.OAuth2.ClientSecret),
 	}
+
+	for _, option := range opts {
+		option(c)
+	}
+
+	return c
 }
 
 func (c *Client) Session() *internal.Session {
@@ -7,18 +7,87 @@ import (
 
 type ConversionsAPI interface {
 	Dataset(ctx context.Context, datasetID string, params Params) (Result, error)
+	DatasetTyped(ctx context.Context, datasetID string, params Params) (Dataset, error)
 	Datasets(ctx context.Context, adAccountID string, params Params) (Result, error)
+	DatasetsTyped(ctx context.Context, adAccountID string, params Params) ([]Dataset, error)
+	CreateDataset(ctx context.Context, adAccountID string, name string, params Params) (string, error)
+	DatasetStats(ctx context.Context, datasetID string, request DatasetStatsRequest) ([]DatasetStats, error)
+	DatasetSharedAdAccounts(ctx context.Context, datasetID string, businessID string) ([]AdAccount, error)
+	DatasetQuality(ctx context.Context, datasetID string) ([]EventQuality, error)
 	UploadEvents(ctx context.Context, datasetID string, params Params) (Result, error)
+	UploadServerEvents(ctx context.Context, datasetID string, events []ServerEvent, params Params) (UploadEventsOutput, error)
+	UploadServerEventsBulk(ctx context.Context, datasetID string, events []ServerEvent, opts UploadEventsOptions) (UploadEventsReport, error)
+}
+
+type UploadEventsOutput struct {
+	EventsReceived int      `json:"events_received"`
+	Messages       []string `json:"messages"`
+	FBTraceID      string   `json:"fbtrace_id"`
+	Suppressed     int      `json:"-"` // events not sent as they were already sent, see WithDeduplicator.
+	MarkErr        error    `json:"-"` // set if sent events couldn't be remembered, see WithDeduplicator.
 }
 
 func (c *Client) Dataset(ctx context.Context, datasetID string, params Params) (Result, error) {
-	return c.session.Get(fmt.Sprintf("/%s", datasetID), params)
+	return c.session.WithContext(ctx).Get(fmt.Sprintf("/%s", datasetID), params)
 }
 
 func (c *Client) Datasets(ctx context.Context, adAccountID string, params Params) (Result, error) {
-	return c.session.Get(fmt.Sprintf("/%s/adspixels", adAccountID), params)
+	return c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/adspixels", adAccountID), params)
 }
 
+// UploadEvents calls the Facebook API with POST at /{dataset_id}/events to send events.
+// The test event code of the client is added unless params set test_event_code, see WithTestEventCode.
 func (c *Client) UploadEvents(ctx context.Context, datasetID string, params Params) (Result, error) {
-	return c.session.Post(fmt.Sprintf("/%s/events", datasetID), params)
+	if _, ok := params["test_event_code"]; !ok && c.testEventCode != "" {
+		if params == nil {
+			params = make(Params)
+		}
+		params["test_event_code"] = c.testEventCode
+	}
+
+	return c.session.WithContext(ctx).Post(fmt.Sprintf("/%s/events", datasetID), params)
+}
+
+// UploadServerEvents calls the Facebook API with POST at /{dataset_id}/events to send events.
+// Customer information in events is normalized and hashed, see UserData.
+//
+// With WithDeduplicator, events already sent to the dataset are suppressed and counted in UploadEventsOutput.Suppressed,
+// and facebook isn't called if all events are suppressed. Once facebook received the events, failing to remember them
+// doesn't fail the call, as retrying it would send them twice. It is reported in UploadEventsOutput.MarkErr instead.
+func (c *Client) UploadServerEvents(ctx context.Context, datasetID string, events []ServerEvent, params Params) (UploadEventsOutput, error) {
+	var suppressed int
+
+	if c.deduplicator != nil {
+		var err error
+		if events, suppressed, err = c.deduplicator.Filter(datasetID, events); err != nil {
+			return UploadEventsOutput{}, fmt.Errorf("facebook: cannot deduplicate events; %w", err)
+		}
+
+		if len(events) == 0 {
+			return UploadEventsOutput{Suppressed: suppressed}, nil
+		}
+	}
+
+	if params == nil {
+		params = make(Params)
+	}
+	params["data"] = events
+
+	res, err := c.UploadEvents(ctx, datasetID, params)
+	if err != nil {
+		return UploadEventsOutput{}, err
+	}
+
+	var markErr error
+
+	if c.deduplicator != nil {
+		if err := c.deduplicator.Mark(datasetID, events); err != nil {
+			markErr = fmt.Errorf("facebook: cannot remember sent events; %w", err)
+		}
+	}
+
+	out, err := DecodeAs[UploadEventsOutput](res)
+	out.Suppressed = suppressed
+	out.MarkErr = markErr
+	return out, err
 }
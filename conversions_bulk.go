@@ -0,0 +1,126 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MaxEventsPerUpload is the maximum number of events facebook accepts in a single call to /{dataset_id}/events.
+const MaxEventsPerUpload = 1000
+
+type UploadEventsOptions struct {
+	ChunkSize   int         // events per call, at most MaxEventsPerUpload. defaults to MaxEventsPerUpload.
+	Concurrency int         // maximum number of calls in flight. defaults to 1.
+	Params      Params      // extra params sent with every call.
+	Upload      EventUpload // identifies offline and CRM uploads, sent with every call.
+	Validate    bool        // validates all events with ValidateServerEvents before sending any.
+}
+
+// UploadEventsChunk is the outcome of sending one chunk of events.
+type UploadEventsChunk struct {
+	Index  int           // position of the chunk, starting at 0.
+	Events []ServerEvent // events of the chunk, to retry a failed chunk.
+	Output UploadEventsOutput
+	Err    error
+}
+
+// UploadEventsReport aggregates the outcome of all chunks sent by UploadServerEventsBulk.
+type UploadEventsReport struct {
+	EventsReceived int
+	Suppressed     int // events not sent as they were already sent, see WithDeduplicator.
+	Messages       []string
+	FBTraceIDs     []string
+	Chunks         []UploadEventsChunk // in the order of events.
+}
+
+// Failed returns the chunks which failed.
+func (r UploadEventsReport) Failed() []UploadEventsChunk {
+	var failed []UploadEventsChunk
+
+	for _, chunk := range r.Chunks {
+		if chunk.Err != nil {
+			failed = append(failed, chunk)
+		}
+	}
+
+	return failed
+}
+
+// UploadServerEventsBulk sends any number of events with UploadServerEvents, split into chunks
+// facebook accepts and sent with bounded concurrency.
+//
+// The report holds the outcome of every chunk so that only failed chunks need to be retried.
+// The returned error joins the errors of all failed chunks.
+func (c *Client) UploadServerEventsBulk(ctx context.Context, datasetID string, events []ServerEvent, opts UploadEventsOptions) (UploadEventsReport, error) {
+	if opts.Validate {
+		if err := ValidateServerEvents(events); err != nil {
+			return UploadEventsReport{}, err
+		}
+	}
+
+	size := opts.ChunkSize
+	if size <= 0 || size > MaxEventsPerUpload {
+		size = MaxEventsPerUpload
+	}
+
+	concurrency := max(opts.Concurrency, 1)
+
+	var chunks []UploadEventsChunk
+	for start := 0; start < len(events); start += size {
+		chunks = append(chunks, UploadEventsChunk{
+			Index:  len(chunks),
+			Events: events[start:min(start+size, len(events))],
+		})
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range chunks {
+		select {
+		case <-ctx.Done():
+			chunks[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(chunk *UploadEventsChunk) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			params := opts.Upload.Params()
+			for k, v := range opts.Params {
+				params[k] = v
+			}
+
+			chunk.Output, chunk.Err = c.UploadServerEvents(ctx, datasetID, chunk.Events, params)
+		}(&chunks[i])
+	}
+
+	wg.Wait()
+
+	report := UploadEventsReport{Chunks: chunks}
+	var errs []error
+
+	for _, chunk := range chunks {
+		if chunk.Err != nil {
+			errs = append(errs, fmt.Errorf("facebook: chunk %d of %d events fails; %w", chunk.Index, len(chunk.Events), chunk.Err))
+			continue
+		}
+
+		report.EventsReceived += chunk.Output.EventsReceived
+		report.Suppressed += chunk.Output.Suppressed
+		report.Messages = append(report.Messages, chunk.Output.Messages...)
+
+		if chunk.Output.FBTraceID != "" {
+			report.FBTraceIDs = append(report.FBTraceIDs, chunk.Output.FBTraceID)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
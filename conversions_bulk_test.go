@@ -0,0 +1,74 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUploadServerEventsBulkChunkSize(t *testing.T) {
+	srv := newEventsTestServer(t)
+	client := newTestClient(srv.Server)
+
+	cases := map[int]string{
+		0:                      "[1000 1000 500]",
+		MaxEventsPerUpload + 1: "[1000 1000 500]",
+		700:                    "[700 700 700 400]",
+	}
+
+	for size, expected := range cases {
+		srv.Reset()
+
+		report, err := client.UploadServerEventsBulk(context.Background(), "d1", testEvents("e", 2500), UploadEventsOptions{ChunkSize: size})
+		if err != nil {
+			t.Fatalf("upload should succeed. [size:%v] [e:%v]", size, err)
+		}
+
+		var sizes []int
+		for _, call := range srv.Calls() {
+			sizes = append(sizes, len(call.EventIDs))
+		}
+
+		if fmt.Sprint(sizes) != expected || report.EventsReceived != 2500 || len(report.Chunks) != len(sizes) {
+			t.Fatalf("unexpected chunks. [size:%v] [expected:%v] [actual:%v] [received:%v]", size, expected, sizes, report.EventsReceived)
+		}
+	}
+}
+
+func TestUploadServerEventsBulkFailedChunk(t *testing.T) {
+	srv := newEventsTestServer(t)
+	srv.Delay = 20 * time.Millisecond
+	client := newTestClient(srv.Server)
+
+	events := testEvents("e", 45)
+	events[25].EventID = "bad"
+
+	report, err := client.UploadServerEventsBulk(context.Background(), "d1", events, UploadEventsOptions{ChunkSize: 10, Concurrency: 2})
+
+	var fbErr *Error
+
+	if !errors.As(err, &fbErr) || fbErr.Code != 100 {
+		t.Fatalf("upload should fail with the error of the failed chunk. [e:%v]", err)
+	}
+
+	failed := report.Failed()
+
+	if len(failed) != 1 || failed[0].Index != 2 || eventIDs(failed[0].Events) != eventIDs(events[20:30]) {
+		t.Fatalf("failed chunk should be reported with its events. [failed:%+v]", failed)
+	}
+
+	if report.EventsReceived != 35 || len(report.Chunks) != 5 {
+		t.Fatalf("other chunks should succeed. [received:%v] [chunks:%v]", report.EventsReceived, len(report.Chunks))
+	}
+
+	if fmt.Sprint(report.Messages) != "[received e0 received e10 received e30 received e40]" ||
+		fmt.Sprint(report.FBTraceIDs) != "[trace-e0 trace-e10 trace-e30 trace-e40]" {
+		t.Fatalf("outputs should be aggregated in the order of chunks. [messages:%v] [traces:%v]", report.Messages, report.FBTraceIDs)
+	}
+
+	if n := srv.MaxInFlight(); n != 2 {
+		t.Fatalf("calls in flight should be bounded by concurrency. [max:%v]", n)
+	}
+}
@@ -0,0 +1,241 @@
+package facebook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// maxNDJSONLineSize is the longest line NewNDJSONCustomerFile reads.
+const maxNDJSONLineSize = 1 << 20
+
+// CustomerFile reads customer data from a CSV or NDJSON stream, mapping its columns to audience schema keys.
+// Rows are read lazily, so that files of any size are sent with UploadCustomerFile without loading them in memory.
+type CustomerFile struct {
+	schema []AudienceSchemaKey
+	next   func() (values []string, line int, err error) // returns io.EOF after the last row.
+	line   int                                           // line of the last row read.
+}
+
+// NewCSVCustomerFile reads a CSV stream whose first line is a header.
+//
+// mapping maps header names to schema keys, e.g. {"E-mail": facebook.SchemaEmail}. Names are matched
+// case-insensitively and columns missing in mapping are ignored. If mapping is nil, headers named after
+// schema keys, e.g. "email" or "FN", are used.
+func NewCSVCustomerFile(r io.Reader, mapping map[string]AudienceSchemaKey) (*CustomerFile, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("facebook: cannot read header of customer file; %w", err)
+	}
+
+	var schema []AudienceSchemaKey
+	var columns []int
+
+	for i, name := range header {
+		if key, ok := mapCustomerFileColumn(mapping, name); ok {
+			schema = append(schema, key)
+			columns = append(columns, i)
+		}
+	}
+
+	if err := validateAudienceSchema(schema); err != nil {
+		return nil, err
+	}
+
+	return &CustomerFile{
+		schema: schema,
+		next: func() ([]string, int, error) {
+			record, err := reader.Read()
+			if err != nil {
+				return nil, 0, err
+			}
+
+			line, _ := reader.FieldPos(0)
+			values := make([]string, len(columns))
+
+			for i, column := range columns {
+				if column >= len(record) {
+					return nil, line, &AudienceRowError{
+						Line:   line,
+						Column: schema[i],
+						Err:    fmt.Errorf("expect %d fields but get %d", len(header), len(record)),
+					}
+				}
+
+				values[i] = record[column]
+			}
+
+			return values, line, nil
+		},
+	}, nil
+}
+
+// NewNDJSONCustomerFile reads a stream of JSON objects, one per line.
+//
+// mapping maps object keys to schema keys and must not be empty. Keys are matched case-insensitively,
+// missing or null values are empty and other keys are ignored.
+func NewNDJSONCustomerFile(r io.Reader, mapping map[string]AudienceSchemaKey) (*CustomerFile, error) {
+	if len(mapping) == 0 {
+		return nil, errors.New("facebook: mapping of NDJSON customer file is empty")
+	}
+
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	schema := make([]AudienceSchemaKey, len(names))
+
+	for i, name := range names {
+		schema[i] = mapping[name]
+	}
+
+	if err := validateAudienceSchema(schema); err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxNDJSONLineSize)
+	line := 0
+
+	return &CustomerFile{
+		schema: schema,
+		next: func() ([]string, int, error) {
+			for scanner.Scan() {
+				line++
+
+				data := bytes.TrimSpace(scanner.Bytes())
+				if len(data) == 0 {
+					continue
+				}
+
+				values, err := decodeNDJSONRow(data, names)
+				if err != nil {
+					return nil, line, &AudienceRowError{Line: line, Err: err}
+				}
+
+				return values, line, nil
+			}
+
+			if err := scanner.Err(); err != nil {
+				return nil, line + 1, fmt.Errorf("line %d: %w", line+1, err)
+			}
+
+			return nil, 0, io.EOF
+		},
+	}, nil
+}
+
+// Schema returns the schema keys of the mapped columns.
+func (f *CustomerFile) Schema() []AudienceSchemaKey {
+	return f.schema
+}
+
+// Rows iterates over raw rows of the file, with values in the order of Schema. Values are neither
+// normalized nor hashed, UploadUsers does it. The file can only be iterated once.
+//
+// Rows missing mapped columns are reported with an *AudienceRowError holding their line, and iteration goes on.
+// Iteration stops at any other error, e.g. a malformed CSV line.
+func (f *CustomerFile) Rows() iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for row := 0; ; row++ {
+			values, line, err := f.next()
+			if err == io.EOF {
+				return
+			}
+
+			f.line = line
+			var rowErr *AudienceRowError
+
+			if err != nil {
+				if !errors.As(err, &rowErr) {
+					yield(nil, fmt.Errorf("facebook: cannot read customer file; %w", err))
+					return
+				}
+
+				rowErr.Row = row
+			}
+
+			if !yield(values, err) {
+				return
+			}
+		}
+	}
+}
+
+// UploadCustomerFile sends all rows of file to an audience with UploadUsers.
+// Invalid rows are skipped and reported in the RejectedRows of the report, with their line.
+func (c *Client) UploadCustomerFile(ctx context.Context, audienceID string, file *CustomerFile, opts AudienceUploadOptions) (*AudienceUploadReport, error) {
+	return c.uploadUserRows(ctx, audienceID, file.Schema(), file.Rows(), func() int { return file.line }, opts)
+}
+
+func mapCustomerFileColumn(mapping map[string]AudienceSchemaKey, name string) (AudienceSchemaKey, bool) {
+	name = strings.TrimSpace(name)
+
+	if mapping == nil {
+		key := AudienceSchemaKey(strings.ToUpper(name))
+		_, ok := audienceColumns[key]
+		return key, ok
+	}
+
+	if key, ok := mapping[name]; ok {
+		return key, true
+	}
+
+	for k, key := range mapping {
+		if strings.EqualFold(k, name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+func decodeNDJSONRow(data []byte, names []string) ([]string, error) {
+	var object map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("invalid JSON object; %w", err)
+	}
+
+	fields := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		fields[strings.ToLower(k)] = v
+	}
+
+	values := make([]string, len(names))
+
+	for i, name := range names {
+		v, ok := object[name]
+		if !ok {
+			v = fields[strings.ToLower(name)]
+		}
+
+		switch v := v.(type) {
+		case nil:
+		case string:
+			values[i] = v
+		case json.Number:
+			values[i] = v.String()
+		default:
+			return nil, fmt.Errorf("value of %q is not a string or a number", name)
+		}
+	}
+
+	return values, nil
+}
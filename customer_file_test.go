@@ -0,0 +1,151 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// customerFileRows reads all rows of a customer file, with "line:error" for invalid rows.
+func customerFileRows(file *CustomerFile) []string {
+	var rows []string
+
+	for values, err := range file.Rows() {
+		var rowErr *AudienceRowError
+
+		switch {
+		case errors.As(err, &rowErr):
+			rows = append(rows, fmt.Sprintf("%d:error", rowErr.Line))
+		case err != nil:
+			rows = append(rows, "stop")
+		default:
+			rows = append(rows, strings.Join(values, "|"))
+		}
+	}
+
+	return rows
+}
+
+func TestNewCSVCustomerFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    string
+		mapping map[string]AudienceSchemaKey
+		schema  string
+		rows    string
+	}{
+		{
+			name:   "schema keys as header",
+			data:   "email,Ignored,fn\n John@Example.com ,x,John\n",
+			schema: "[EMAIL FN]",
+			rows:   "[ John@Example.com |John]",
+		},
+		{
+			name:    "mapping",
+			data:    "E-Mail,Phone Number,Other\na@x.io,+1 650 555 1212,x\nb@x.io\nc@x.io,,y\n",
+			mapping: map[string]AudienceSchemaKey{"e-mail": SchemaEmail, "Phone Number": SchemaPhone},
+			schema:  "[EMAIL PHONE]",
+			rows:    "[a@x.io|+1 650 555 1212 3:error c@x.io|]",
+		},
+		{
+			name:    "quoted field over several lines",
+			data:    "email,fn\n\"a@x.io\",\"Jo\nhn\"\nb@x.io\n",
+			mapping: nil,
+			schema:  "[EMAIL FN]",
+			rows:    "[a@x.io|Jo\nhn 4:error]",
+		},
+		{
+			name:   "malformed line",
+			data:   "email\na@x.io\n\"b@x.io\n",
+			schema: "[EMAIL]",
+			rows:   "[a@x.io stop]",
+		},
+	}
+
+	for _, c := range cases {
+		file, err := NewCSVCustomerFile(strings.NewReader(c.data), c.mapping)
+
+		if err != nil {
+			t.Fatalf("cannot read customer file. [case:%v] [e:%v]", c.name, err)
+		}
+
+		if fmt.Sprint(file.Schema()) != c.schema {
+			t.Fatalf("unexpected schema. [case:%v] [expected:%v] [actual:%v]", c.name, c.schema, file.Schema())
+		}
+
+		if rows := fmt.Sprint(customerFileRows(file)); rows != c.rows {
+			t.Fatalf("unexpected rows. [case:%v] [expected:%v] [actual:%v]", c.name, c.rows, rows)
+		}
+	}
+
+	if _, err := NewCSVCustomerFile(strings.NewReader("foo,bar\n"), nil); err == nil {
+		t.Fatalf("header without schema keys should fail.")
+	}
+}
+
+func TestNewNDJSONCustomerFile(t *testing.T) {
+	data := `{"Email": "a@x.io", "zip": 94025, "other": true}
+
+{"email": null, "zip": "1000-001"}
+not json
+{"email": "b@x.io", "zip": ["list"]}
+{"email": "c@x.io"}
+`
+	file, err := NewNDJSONCustomerFile(strings.NewReader(data), map[string]AudienceSchemaKey{"email": SchemaEmail, "zip": SchemaZip})
+
+	if err != nil {
+		t.Fatalf("cannot read customer file. [e:%v]", err)
+	}
+
+	if fmt.Sprint(file.Schema()) != "[EMAIL ZIP]" {
+		t.Fatalf("schema should be in the order of mapping keys. [schema:%v]", file.Schema())
+	}
+
+	expected := "[a@x.io|94025 |1000-001 4:error 5:error c@x.io|]"
+
+	if rows := fmt.Sprint(customerFileRows(file)); rows != expected {
+		t.Fatalf("unexpected rows. [expected:%v] [actual:%v]", expected, rows)
+	}
+
+	if _, err := NewNDJSONCustomerFile(strings.NewReader(data), nil); err == nil {
+		t.Fatalf("empty mapping should fail.")
+	}
+}
+
+func TestUploadCustomerFile(t *testing.T) {
+	var calls []audienceUploadCall
+	srv := newAudienceUploadTestServer(t, &calls)
+	client := newTestClient(srv)
+
+	file, err := NewCSVCustomerFile(strings.NewReader("email,country\n A@X.io ,US\ninvalid,US\nb@x.io\n,\nc@x.io,DK\n"), nil)
+
+	if err != nil {
+		t.Fatalf("cannot read customer file. [e:%v]", err)
+	}
+
+	report, err := client.UploadCustomerFile(context.Background(), "1", file, AudienceUploadOptions{})
+
+	if err != nil {
+		t.Fatalf("upload should succeed. [e:%v]", err)
+	}
+
+	expected := fmt.Sprint([][]any{
+		{sha256Hex("a@x.io"), sha256Hex("us")},
+		{sha256Hex("c@x.io"), sha256Hex("dk")},
+	})
+
+	if len(calls) != 1 || fmt.Sprint(calls[0].Payload.Data) != expected {
+		t.Fatalf("rows should be normalized and hashed once. [expected:%v] [calls:%+v]", expected, calls)
+	}
+
+	var lines []string
+	for _, rowErr := range report.RejectedRows {
+		lines = append(lines, fmt.Sprintf("%d:%d:%s", rowErr.Line, rowErr.Row, rowErr.Column))
+	}
+
+	if fmt.Sprint(lines) != "[3:1:EMAIL 4:2:COUNTRY 5:3:]" {
+		t.Fatalf("rejected rows should have their line. [rejected:%v]", lines)
+	}
+}
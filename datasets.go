@@ -0,0 +1,210 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Dataset is a dataset receiving Conversions API events, also known as a pixel.
+// See https://developers.facebook.com/docs/marketing-api/reference/ads-pixel.
+type Dataset struct {
+	ID                      string        `facebook:"id,required" json:"id"`
+	Name                    string        `json:"name"`
+	CreationTime            string        `json:"creation_time"`   // ISO 8601 datetime.
+	LastFiredTime           string        `json:"last_fired_time"` // ISO 8601 datetime, empty if no event was received.
+	IsUnavailable           bool          `json:"is_unavailable"`
+	DataUseSetting          string        `json:"data_use_setting"`
+	OwnerBusiness           *DatasetOwner `json:"owner_business"`
+	OwnerAdAccount          *DatasetOwner `json:"owner_ad_account"`
+	EnableAutomaticMatching bool          `json:"enable_automatic_matching"`
+	AutomaticMatchingFields []string      `json:"automatic_matching_fields"`
+	FirstPartyCookieStatus  string        `json:"first_party_cookie_status"`
+	Code                    string        `json:"code"` // pixel base code, only set when requested.
+}
+
+// DatasetOwner is the business or ad account owning a dataset.
+type DatasetOwner struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var datasetFields = []string{"id", "name", "creation_time", "last_fired_time", "is_unavailable", "data_use_setting",
+	"owner_business", "owner_ad_account", "enable_automatic_matching", "automatic_matching_fields",
+	"first_party_cookie_status"}
+
+// DatasetTyped is Dataset decoded into a Dataset.
+// Fields of Dataset are requested unless params selects fields.
+func (c *Client) DatasetTyped(ctx context.Context, datasetID string, params Params) (Dataset, error) {
+	res, err := c.Dataset(ctx, datasetID, withDefaultFields(params, datasetFields...))
+	if err != nil {
+		return Dataset{}, err
+	}
+
+	return DecodeAs[Dataset](res)
+}
+
+// DatasetsTyped is Datasets decoded into Dataset values.
+// Only the first page is returned, use Paging and All to read all datasets.
+func (c *Client) DatasetsTyped(ctx context.Context, adAccountID string, params Params) ([]Dataset, error) {
+	return decodeData[Dataset](c.Datasets(ctx, adAccountID, withDefaultFields(params, datasetFields...)))
+}
+
+// CreateDataset calls the Facebook API with POST at /{ad_account_id}/adspixels to create a dataset.
+// adAccountID is the same as in Datasets. It returns the ID of the new dataset.
+func (c *Client) CreateDataset(ctx context.Context, adAccountID string, name string, params Params) (string, error) {
+	p := Params{"name": name}
+	for k, v := range params {
+		p[k] = v
+	}
+
+	res, err := c.session.WithContext(ctx).Post(fmt.Sprintf("/%s/adspixels", adAccountID), p)
+	if err != nil {
+		return "", err
+	}
+
+	return DecodeFieldAs[string](res, "id")
+}
+
+type DatasetStatsAggregation = string
+
+// Aggregations of dataset stats.
+const (
+	DatasetStatsByEvent                DatasetStatsAggregation = "event"
+	DatasetStatsByEventSource          DatasetStatsAggregation = "event_source"
+	DatasetStatsByMatchKeys            DatasetStatsAggregation = "match_keys"
+	DatasetStatsByEventTotalCounts     DatasetStatsAggregation = "event_total_counts"
+	DatasetStatsByEventDetectionMethod DatasetStatsAggregation = "event_detection_method"
+	DatasetStatsByDeviceType           DatasetStatsAggregation = "device_type"
+	DatasetStatsByHost                 DatasetStatsAggregation = "host"
+	DatasetStatsByURL                  DatasetStatsAggregation = "url"
+)
+
+type DatasetStatsRequest struct {
+	Aggregation DatasetStatsAggregation // defaults to DatasetStatsByEvent.
+	Event       string                  // counts only this event, e.g. "Purchase".
+	StartTime   time.Time               // defaults to facebook's default.
+	EndTime     time.Time               // defaults to now.
+	Params      Params                  // extra params.
+}
+
+// DatasetStats counts events received by a dataset in an hour.
+type DatasetStats struct {
+	Aggregation DatasetStatsAggregation `json:"aggregation"`
+	StartTime   string                  `json:"start_time"` // ISO 8601 datetime.
+	Data        []DatasetStatsCount     `json:"data"`
+}
+
+// DatasetStatsCount is the number of events with a value of the aggregation,
+// e.g. the number of "Purchase" events when aggregating by event.
+type DatasetStatsCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// DatasetStats calls the Facebook API with GET at /{dataset_id}/stats to count events received by a dataset.
+func (c *Client) DatasetStats(ctx context.Context, datasetID string, request DatasetStatsRequest) ([]DatasetStats, error) {
+	params := Params{"aggregation": DatasetStatsByEvent}
+
+	if request.Aggregation != "" {
+		params["aggregation"] = request.Aggregation
+	}
+
+	if request.Event != "" {
+		params["event"] = request.Event
+	}
+
+	if !request.StartTime.IsZero() {
+		params["start_time"] = request.StartTime.Unix()
+	}
+
+	if !request.EndTime.IsZero() {
+		params["end_time"] = request.EndTime.Unix()
+	}
+
+	for k, v := range request.Params {
+		params[k] = v
+	}
+
+	return decodeData[DatasetStats](c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/stats", datasetID), params))
+}
+
+// DatasetSharedAdAccounts calls the Facebook API with GET at /{dataset_id}/shared_accounts and returns
+// all ad accounts of a business the dataset is shared with.
+func (c *Client) DatasetSharedAdAccounts(ctx context.Context, datasetID string, businessID string) ([]AdAccount, error) {
+	params := FieldsParams(adAccountFields...)
+	params["business"] = businessID
+
+	res, err := c.session.WithContext(ctx).Get(fmt.Sprintf("/%s/shared_accounts", datasetID), params)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := c.Paging(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []AdAccount
+
+	for account, err := range All[AdAccount](pr, PagingLimit{}) {
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// EventQuality is the quality of an event received by a dataset from websites.
+type EventQuality struct {
+	EventName         string            `json:"event_name"`
+	EventMatchQuality EventMatchQuality `json:"event_match_quality"`
+}
+
+// EventMatchQuality tells how well customer information of events matches facebook accounts.
+type EventMatchQuality struct {
+	CompositeScore   float64            `json:"composite_score"` // from 0 to 10.
+	MatchKeyFeedback []MatchKeyFeedback `json:"match_key_feedback"`
+	Diagnostics      []EventDiagnostic  `json:"diagnostics"`
+}
+
+// MatchKeyFeedback is the share of events with a customer information parameter, e.g. "email".
+type MatchKeyFeedback struct {
+	Identifier string          `json:"identifier"`
+	Coverage   DatasetCoverage `json:"coverage"`
+}
+
+type DatasetCoverage struct {
+	Percentage float64 `json:"percentage"`
+}
+
+// EventDiagnostic is a problem found in events, with a way to solve it.
+type EventDiagnostic struct {
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	Solution           string  `json:"solution"`
+	Percentage         float64 `json:"percentage"` // share of affected events.
+	AffectedEventCount int64   `json:"affected_event_count"`
+}
+
+// DatasetQuality calls the Facebook API with GET at /dataset_quality to read the event match quality
+// and diagnostics of events a dataset received from websites.
+// See https://developers.facebook.com/docs/marketing-api/conversions-api/dataset-quality-api.
+func (c *Client) DatasetQuality(ctx context.Context, datasetID string) ([]EventQuality, error) {
+	res, err := c.session.WithContext(ctx).Get("/dataset_quality", Params{
+		"dataset_id": datasetID,
+		"fields":     "web{event_name,event_match_quality}",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Get("web") == nil {
+		return nil, nil
+	}
+
+	return DecodeFieldAs[[]EventQuality](res, "web")
+}
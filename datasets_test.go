@@ -0,0 +1,83 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDatasets(t *testing.T) {
+	var stats []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		query := r.URL.Query()
+
+		switch {
+		case r.URL.Path == "/d1/stats":
+			stats = append(stats, fmt.Sprint(query.Get("aggregation"), " ", query.Get("event"), " ", query.Get("start_time"), " ", query.Get("end_time")))
+			_, _ = w.Write([]byte(`{"data": [{"aggregation": "event", "start_time": "2023-11-14T22:00:00+0000", "data": [{"value": "Purchase", "count": 3}]}]}`))
+		case r.URL.Path == "/dataset_quality":
+			if query.Get("dataset_id") != "d1" || query.Get("fields") != "web{event_name,event_match_quality}" {
+				t.Errorf("unexpected quality query. [query:%v]", r.URL.RawQuery)
+			}
+
+			_, _ = w.Write([]byte(`{"web": [{"event_name": "Purchase", "event_match_quality": {
+				"composite_score": 7.5,
+				"match_key_feedback": [{"identifier": "email", "coverage": {"percentage": 80}}],
+				"diagnostics": [{"name": "missing_fbc", "percentage": 40, "affected_event_count": 12}]
+			}}]}`))
+		case r.URL.Path == "/d1/shared_accounts" && query.Get("after") == "":
+			if query.Get("business") != "b1" {
+				t.Errorf("business should be set. [query:%v]", r.URL.RawQuery)
+			}
+
+			_, _ = fmt.Fprintf(w, `{"data": [{"id": "act_1"}], "paging": {"cursors": {"after": "c1"}, "next": "http://%s/d1/shared_accounts?after=c1"}}`, r.Host)
+		case r.URL.Path == "/d1/shared_accounts":
+			_, _ = w.Write([]byte(`{"data": [{"id": "act_2"}]}`))
+		default:
+			t.Errorf("unexpected request. [path:%v]", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	ctx := context.Background()
+
+	counts, err := client.DatasetStats(ctx, "d1", DatasetStatsRequest{})
+	if err != nil || len(counts) != 1 || fmt.Sprint(counts[0].Data) != "[{Purchase 3}]" {
+		t.Fatalf("unexpected stats. [stats:%+v] [e:%v]", counts, err)
+	}
+
+	_, err = client.DatasetStats(ctx, "d1", DatasetStatsRequest{
+		Aggregation: DatasetStatsByHost,
+		Event:       "Purchase",
+		StartTime:   time.Unix(1700000000, 0),
+		EndTime:     time.Unix(1700003600, 0),
+	})
+	if err != nil {
+		t.Fatalf("cannot read stats. [e:%v]", err)
+	}
+
+	if expected := "[event    host Purchase 1700000000 1700003600]"; fmt.Sprint(stats) != expected {
+		t.Fatalf("unexpected stats params. [expected:%v] [actual:%v]", expected, stats)
+	}
+
+	quality, err := client.DatasetQuality(ctx, "d1")
+	if err != nil || len(quality) != 1 {
+		t.Fatalf("unexpected quality. [quality:%+v] [e:%v]", quality, err)
+	}
+
+	if q := quality[0].EventMatchQuality; quality[0].EventName != "Purchase" || q.CompositeScore != 7.5 ||
+		fmt.Sprint(q.MatchKeyFeedback) != "[{email {80}}]" || q.Diagnostics[0].AffectedEventCount != 12 {
+		t.Fatalf("unexpected event match quality. [quality:%+v]", quality[0])
+	}
+
+	accounts, err := client.DatasetSharedAdAccounts(ctx, "d1", "b1")
+	if err != nil || len(accounts) != 2 || accounts[0].ID != "act_1" || accounts[1].ID != "act_2" {
+		t.Fatalf("all pages of shared ad accounts should be read. [accounts:%+v] [e:%v]", accounts, err)
+	}
+}
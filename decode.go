@@ -0,0 +1,47 @@
+package facebook
+
+// DecodeAs decodes res into a new value of type T. See Result.Decode for the supported struct tags.
+func DecodeAs[T any](res Result) (T, error) {
+	var v T
+	if err := res.Decode(&v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// DecodeFieldAs decodes the field of res into a new value of type T. See Result.DecodeField.
+func DecodeFieldAs[T any](res Result, field string) (T, error) {
+	var v T
+	if err := res.DecodeField(field, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// decodeData decodes the "data" field of a paging response, or returns the error of the call.
+func decodeData[T any](res Result, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeFieldAs[[]T](res, "data")
+}
+
+// withDefaultFields returns params with fields set unless the caller already selected some.
+func withDefaultFields(params Params, fields ...string) Params {
+	if _, ok := params["fields"]; ok {
+		return params
+	}
+
+	p := FieldsParams(fields...)
+
+	for k, v := range params {
+		p[k] = v
+	}
+
+	return p
+}
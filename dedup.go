@@ -0,0 +1,192 @@
+package facebook
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSeenSetCapacity = 100000
+
+	// defaultSeenSetTTL matches the window in which facebook deduplicates events.
+	defaultSeenSetTTL = 48 * time.Hour
+)
+
+// SeenSet remembers events already sent, by dataset, event name and event ID.
+// Implementations must be safe for concurrent use.
+type SeenSet interface {
+	// Contains reports whether key was added and hasn't expired.
+	Contains(key string) (bool, error)
+
+	// Add adds keys to the set.
+	Add(keys ...string) error
+}
+
+// LRUSeenSet is a SeenSet keeping the most recently added keys in memory for a limited time.
+type LRUSeenSet struct {
+	capacity int
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	order   *list.List // of *seenEntry, most recently added first.
+	entries map[string]*list.Element
+}
+
+type seenEntry struct {
+	key     string
+	expires time.Time
+}
+
+var _ SeenSet = (*LRUSeenSet)(nil)
+
+// NewLRUSeenSet creates a set keeping at most capacity keys for ttl.
+// Zero values default to 100000 keys and 48 hours, the window in which facebook deduplicates events.
+func NewLRUSeenSet(capacity int, ttl time.Duration) *LRUSeenSet {
+	if capacity <= 0 {
+		capacity = defaultSeenSetCapacity
+	}
+
+	if ttl <= 0 {
+		ttl = defaultSeenSetTTL
+	}
+
+	return &LRUSeenSet{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (s *LRUSeenSet) Contains(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+
+	if time.Now().After(elem.Value.(*seenEntry).expires) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s *LRUSeenSet) Add(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(s.ttl)
+
+	for _, key := range keys {
+		if elem, ok := s.entries[key]; ok {
+			elem.Value.(*seenEntry).expires = expires
+			s.order.MoveToFront(elem)
+			continue
+		}
+
+		s.entries[key] = s.order.PushFront(&seenEntry{key: key, expires: expires})
+
+		if s.order.Len() > s.capacity {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.entries, oldest.Value.(*seenEntry).key)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of keys in the set, including expired keys not evicted yet.
+func (s *LRUSeenSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+// Deduplicator suppresses events already sent, so that retries don't send the same event twice.
+// Events are identified by dataset, event name and event ID, as facebook deduplicates events per dataset;
+// events without an event ID are always sent.
+//
+// Events are remembered once facebook receives them, so that failed uploads can be retried.
+// Duplicates sent by concurrent calls, e.g. in different chunks of UploadServerEventsBulk, are not suppressed.
+type Deduplicator struct {
+	seen       SeenSet
+	suppressed atomic.Int64
+}
+
+// NewDeduplicator creates a Deduplicator remembering events in seen.
+// If seen is nil, events are remembered in memory with NewLRUSeenSet(0, 0).
+func NewDeduplicator(seen SeenSet) *Deduplicator {
+	if seen == nil {
+		seen = NewLRUSeenSet(0, 0)
+	}
+
+	return &Deduplicator{seen: seen}
+}
+
+// Suppressed returns the number of events suppressed so far.
+func (d *Deduplicator) Suppressed() int64 {
+	return d.suppressed.Load()
+}
+
+// Filter returns events which haven't been sent to the dataset yet, without duplicates, and the number of suppressed events.
+func (d *Deduplicator) Filter(datasetID string, events []ServerEvent) ([]ServerEvent, int, error) {
+	filtered := make([]ServerEvent, 0, len(events))
+	batch := make(map[string]bool)
+
+	for _, event := range events {
+		if event.EventID == "" {
+			filtered = append(filtered, event)
+			continue
+		}
+
+		key := dedupKey(datasetID, event)
+
+		if batch[key] {
+			continue
+		}
+
+		seen, err := d.seen.Contains(key)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if !seen {
+			batch[key] = true
+			filtered = append(filtered, event)
+		}
+	}
+
+	suppressed := len(events) - len(filtered)
+	d.suppressed.Add(int64(suppressed))
+	return filtered, suppressed, nil
+}
+
+// Mark remembers events as sent to the dataset.
+func (d *Deduplicator) Mark(datasetID string, events []ServerEvent) error {
+	keys := make([]string, 0, len(events))
+
+	for _, event := range events {
+		if event.EventID != "" {
+			keys = append(keys, dedupKey(datasetID, event))
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return d.seen.Add(keys...)
+}
+
+func dedupKey(datasetID string, event ServerEvent) string {
+	return datasetID + "\x00" + event.EventName + "\x00" + event.EventID
+}
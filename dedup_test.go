@@ -0,0 +1,95 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// failingSeenSet is a SeenSet which can't remember anything.
+type failingSeenSet struct{}
+
+func (failingSeenSet) Contains(string) (bool, error) { return false, nil }
+func (failingSeenSet) Add(...string) error           { return errors.New("seen set unavailable") }
+
+func TestDeduplicator(t *testing.T) {
+	dedup := NewDeduplicator(nil)
+	events := []ServerEvent{
+		{EventName: "Purchase", EventID: "1"},
+		{EventName: "Purchase", EventID: "1"},
+		{EventName: "Lead", EventID: "1"},
+		{EventName: "Purchase"},
+	}
+
+	filtered, suppressed, err := dedup.Filter("d1", events)
+	if err != nil || len(filtered) != 3 || suppressed != 1 {
+		t.Fatalf("duplicates within events should be suppressed. [filtered:%v] [suppressed:%v] [e:%v]", filtered, suppressed, err)
+	}
+
+	if err := dedup.Mark("d1", filtered); err != nil {
+		t.Fatalf("cannot mark events. [e:%v]", err)
+	}
+
+	filtered, suppressed, _ = dedup.Filter("d1", events)
+	if len(filtered) != 1 || filtered[0].EventID != "" || suppressed != 3 {
+		t.Fatalf("events sent to the dataset should be suppressed. [filtered:%v] [suppressed:%v]", filtered, suppressed)
+	}
+
+	filtered, suppressed, _ = dedup.Filter("d2", events)
+	if len(filtered) != 3 || suppressed != 1 {
+		t.Fatalf("events sent to another dataset should not be suppressed. [filtered:%v] [suppressed:%v]", filtered, suppressed)
+	}
+
+	if dedup.Suppressed() != 5 {
+		t.Fatalf("unexpected suppressed count. [suppressed:%v]", dedup.Suppressed())
+	}
+}
+
+func TestLRUSeenSet(t *testing.T) {
+	set := NewLRUSeenSet(2, time.Hour)
+	_ = set.Add("a", "b", "c")
+
+	if ok, _ := set.Contains("a"); ok || set.Len() != 2 {
+		t.Fatalf("oldest key should be evicted. [len:%v]", set.Len())
+	}
+
+	expiring := NewLRUSeenSet(0, time.Nanosecond)
+	_ = expiring.Add("a")
+	time.Sleep(time.Millisecond)
+
+	if ok, _ := expiring.Contains("a"); ok || expiring.Len() != 0 {
+		t.Fatalf("expired key should be evicted. [len:%v]", expiring.Len())
+	}
+}
+
+func TestUploadServerEventsDeduplicated(t *testing.T) {
+	srv := newEventsTestServer(t)
+	client := newTestClient(srv.Server)
+	client.deduplicator = NewDeduplicator(nil)
+	events := []ServerEvent{{EventName: "Purchase", EventID: "1", EventTime: time.Now(), ActionSource: "website"}}
+	ctx := context.Background()
+
+	for _, dataset := range []string{"d1", "d1", "d2"} {
+		if _, err := client.UploadServerEvents(ctx, dataset, events, nil); err != nil {
+			t.Fatalf("upload should succeed. [dataset:%v] [e:%v]", dataset, err)
+		}
+	}
+
+	var calls []string
+	for _, call := range srv.Calls() {
+		calls = append(calls, call.Dataset)
+	}
+
+	if fmt.Sprint(calls) != "[d1 d2]" {
+		t.Fatalf("event should be sent once to every dataset. [calls:%v]", calls)
+	}
+
+	client.deduplicator = NewDeduplicator(failingSeenSet{})
+	out, err := client.UploadServerEvents(ctx, "d3", events, nil)
+
+	if err != nil || out.EventsReceived != 1 || out.MarkErr == nil {
+		t.Fatalf("failing to remember sent events should not fail the upload. [out:%+v] [e:%v]", out, err)
+	}
+}
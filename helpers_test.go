@@ -0,0 +1,159 @@
+package facebook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dreamdata-io/facebook/internal"
+)
+
+// newTestClient creates a client calling srv instead of facebook.
+func newTestClient(srv *httptest.Server) *Client {
+	return &Client{session: &internal.Session{BaseURL: srv.URL + "/"}}
+}
+
+// testEvents creates locally valid events with IDs "prefix0", "prefix1"...
+func testEvents(prefix string, n int) []ServerEvent {
+	events := make([]ServerEvent, n)
+
+	for i := range events {
+		events[i] = ServerEvent{
+			EventName:    "Lead",
+			EventID:      fmt.Sprint(prefix, i),
+			EventTime:    time.Now(),
+			ActionSource: ActionSourceEmail,
+			UserData:     UserData{Emails: []string{"john@example.com"}},
+		}
+	}
+
+	return events
+}
+
+func eventIDs(events []ServerEvent) string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.EventID
+	}
+
+	return strings.Join(ids, " ")
+}
+
+// eventsTestCall is a call to /{dataset_id}/events received by an eventsTestServer.
+type eventsTestCall struct {
+	Dataset  string
+	EventIDs []string
+	Form     url.Values
+	Rejected bool
+}
+
+// eventsTestServer answers calls to /{dataset_id}/events like facebook. Events are rejected as invalid
+// if the dataset is "unknown" or an event ID starts with "bad", and accepted otherwise.
+type eventsTestServer struct {
+	*httptest.Server
+	Delay time.Duration // delay of every answer.
+
+	mu          sync.Mutex
+	calls       []eventsTestCall
+	inFlight    int
+	maxInFlight int
+}
+
+func newEventsTestServer(t *testing.T) *eventsTestServer {
+	s := &eventsTestServer{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []struct {
+			EventID string `json:"event_id"`
+		}
+
+		if err := json.Unmarshal([]byte(r.FormValue("data")), &events); err != nil {
+			t.Errorf("invalid data param. [e:%v]", err)
+		}
+
+		call := eventsTestCall{
+			Dataset: strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/events"),
+			Form:    r.Form,
+		}
+
+		call.Rejected = call.Dataset == "unknown"
+
+		for _, event := range events {
+			call.EventIDs = append(call.EventIDs, event.EventID)
+			call.Rejected = call.Rejected || strings.HasPrefix(event.EventID, "bad")
+		}
+
+		s.mu.Lock()
+		s.calls = append(s.calls, call)
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		s.mu.Unlock()
+
+		time.Sleep(s.Delay)
+
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+
+		w.Header().Add("Content-Type", "application/json")
+
+		if call.Rejected {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"message": "Invalid parameter", "type": "OAuthException", "code": 100}}`))
+			return
+		}
+
+		first := ""
+		if len(call.EventIDs) > 0 {
+			first = call.EventIDs[0]
+		}
+
+		_, _ = fmt.Fprintf(w, `{"events_received": %d, "messages": ["received %s"], "fbtrace_id": "trace-%s"}`,
+			len(events), first, first)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Calls returns all calls received so far.
+func (s *eventsTestServer) Calls() []eventsTestCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]eventsTestCall(nil), s.calls...)
+}
+
+// Received returns the IDs of all accepted events, in the order they were received.
+func (s *eventsTestServer) Received() []string {
+	var ids []string
+
+	for _, call := range s.Calls() {
+		if !call.Rejected {
+			ids = append(ids, call.EventIDs...)
+		}
+	}
+
+	return ids
+}
+
+// MaxInFlight returns the largest number of calls answered at the same time.
+func (s *eventsTestServer) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.maxInFlight
+}
+
+// Reset forgets all calls.
+func (s *eventsTestServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls, s.maxInFlight = nil, 0
+}
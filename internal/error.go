@@ -9,6 +9,7 @@ package internal
 
 import (
 	"fmt"
+	"time"
 )
 
 // Error represents Facebook API error.
@@ -39,3 +40,14 @@ type UnmarshalError struct {
 func (e *UnmarshalError) Error() string {
 	return fmt.Sprintf("%s [err:%v]", e.Message, e.Err)
 }
+
+// ThrottledError is returned when a Throttler rejects a call without sending it to facebook.
+type ThrottledError struct {
+	Key        string        // Usage bucket, e.g. "app" or "ad_account:123".
+	Usage      float64       // Last known usage percentage of the bucket.
+	RetryAfter time.Duration // Estimated time until the bucket is usable again. It can be zero if unknown.
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("facebook: call is throttled locally [key:%s] [usage:%v] [retry_after:%v]", e.Key, e.Usage, e.RetryAfter)
+}
@@ -10,7 +10,7 @@ package internal
 import (
 	"bytes"
 	"fmt"
-	"net/http"
+	"iter"
 )
 
 // PagingResult represents facebook API call result with paging information.
@@ -21,6 +21,12 @@ type PagingResult struct {
 	next     string
 }
 
+// PagingLimit limits how far paging iterators walk. Zero values mean no limit.
+type PagingLimit struct {
+	MaxPages int // maximum number of pages to read, including the current one.
+	MaxItems int // maximum number of items to yield.
+}
+
 type pagingData struct {
 	Data      []Result `facebook:",required"`
 	Paging    *pagingNavigator
@@ -97,6 +103,57 @@ func (pr *PagingResult) Next() (noMore bool, err error) {
 	return pr.navigate(&pr.next)
 }
 
+// Pages returns an iterator over the data of current page and all next pages.
+//
+// Iteration stops when there is no next page, limit is reached, the session context is done
+// or facebook returns an error. Errors are yielded with a nil page.
+//
+// The iterator moves pr forward while reading next pages, so it should be used only once.
+func (pr *PagingResult) Pages(limit PagingLimit) iter.Seq2[[]Result, error] {
+	return func(yield func([]Result, error) bool) {
+		pages, items := 0, 0
+
+		for {
+			data := pr.Data()
+
+			if limit.MaxItems > 0 && items+len(data) > limit.MaxItems {
+				data = data[:limit.MaxItems-items]
+			}
+
+			pages++
+			items += len(data)
+
+			if !yield(data, nil) {
+				return
+			}
+
+			if limit.MaxPages > 0 && pages >= limit.MaxPages {
+				return
+			}
+
+			if limit.MaxItems > 0 && items >= limit.MaxItems {
+				return
+			}
+
+			if err := pr.session.Context().Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			noMore, err := pr.Next()
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if noMore {
+				return
+			}
+		}
+	}
+}
+
 // HasPrevious checks whether there is previous page.
 func (pr *PagingResult) HasPrevious() bool {
 	return pr.previous != ""
@@ -129,16 +186,8 @@ func (pr *PagingResult) navigate(url *string) (noMore bool, err error) {
 		pagingURL = buf.String()
 	}
 
-	var request *http.Request
 	var res Result
-
-	request, err = http.NewRequest("GET", pagingURL, nil)
-
-	if err != nil {
-		return
-	}
-
-	res, err = pr.session.Request(request)
+	res, err = pr.session.getPage(pagingURL)
 
 	if err != nil {
 		return
@@ -0,0 +1,256 @@
+// A facebook graph api client in go.
+// https://github.com/huandu/facebook/
+//
+// Copyright 2012, Huan Du
+// Licensed under the MIT license
+// https://github.com/huandu/facebook/blob/master/LICENSE
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newPagingTestServer(t *testing.T, numPages int) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		next := ""
+
+		if page+1 < numPages {
+			next = fmt.Sprintf(`, "paging": {"next": "%s/v3.0/items?page=%d"}`, srv.URL, page+1)
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data": [{"id": "%d-0"}, {"id": "%d-1"}]%s}`, page, page, next)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPagingResultPages(t *testing.T) {
+	srv := newPagingTestServer(t, 3)
+	session := &Session{
+		Version: "v3.0",
+		BaseURL: srv.URL + "/",
+	}
+
+	test := func(limit PagingLimit, expected []string) {
+		res, err := session.Get("/items", nil)
+
+		if err != nil {
+			t.Fatalf("cannot get first page. [e:%v]", err)
+		}
+
+		pr, err := res.Paging(session)
+
+		if err != nil {
+			t.Fatalf("cannot create paging result. [e:%v]", err)
+		}
+
+		var ids []string
+
+		for page, err := range pr.Pages(limit) {
+			if err != nil {
+				t.Fatalf("cannot read page. [e:%v]", err)
+			}
+
+			for _, item := range page {
+				ids = append(ids, item.Get("id").(string))
+			}
+		}
+
+		if fmt.Sprint(ids) != fmt.Sprint(expected) {
+			t.Fatalf("unexpected items. [limit:%v] [expected:%v] [actual:%v]", limit, expected, ids)
+		}
+	}
+
+	test(PagingLimit{}, []string{"0-0", "0-1", "1-0", "1-1", "2-0", "2-1"})
+	test(PagingLimit{MaxPages: 2}, []string{"0-0", "0-1", "1-0", "1-1"})
+	test(PagingLimit{MaxItems: 3}, []string{"0-0", "0-1", "1-0"})
+}
+
+func TestPagingResultPagesCanceled(t *testing.T) {
+	srv := newPagingTestServer(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := (&Session{
+		Version: "v3.0",
+		BaseURL: srv.URL + "/",
+	}).WithContext(ctx)
+
+	res, err := session.Get("/items", nil)
+
+	if err != nil {
+		t.Fatalf("cannot get first page. [e:%v]", err)
+	}
+
+	pr, _ := res.Paging(session)
+	numPages := 0
+
+	for _, err := range pr.Pages(PagingLimit{}) {
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("iteration should stop with context error. [e:%v]", err)
+			}
+
+			break
+		}
+
+		numPages++
+		cancel()
+	}
+
+	if numPages != 1 {
+		t.Fatalf("only first page should be read. [pages:%v]", numPages)
+	}
+}
+
+func TestPagingResultPagesRetry(t *testing.T) {
+	var srv *httptest.Server
+	numFailures := 0
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+
+		if r.URL.Query().Get("page") == "1" && numFailures == 0 {
+			numFailures++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"message": "Application request limit reached", "code": 4}}`))
+			return
+		}
+
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "1-0"}]}`))
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"data": [{"id": "0-0"}], "paging": {"next": "%s/v3.0/act_1/items?page=1"}}`, srv.URL)
+	}))
+	defer srv.Close()
+
+	var attempts []RetryAttempt
+	session := &Session{
+		Version: "v3.0",
+		BaseURL: srv.URL + "/",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			OnAttempt: func(attempt RetryAttempt) {
+				attempts = append(attempts, attempt)
+			},
+		},
+	}
+
+	res, err := session.Get("/act_1/items", nil)
+
+	if err != nil {
+		t.Fatalf("cannot get first page. [e:%v]", err)
+	}
+
+	pr, _ := res.Paging(session)
+	var ids []string
+
+	for page, err := range pr.Pages(PagingLimit{}) {
+		if err != nil {
+			t.Fatalf("next page should succeed after retry. [e:%v]", err)
+		}
+
+		for _, item := range page {
+			ids = append(ids, item.Get("id").(string))
+		}
+	}
+
+	if fmt.Sprint(ids) != "[0-0 1-0]" {
+		t.Fatalf("unexpected items. [ids:%v]", ids)
+	}
+
+	if len(attempts) != 1 || attempts[0].Path != "/act_1/items" || !attempts[0].WillRetry {
+		t.Fatalf("failed page should be retried. [attempts:%v]", attempts)
+	}
+}
+
+func TestPagingResultPagesThrottled(t *testing.T) {
+	// the second page reports the app usage limit, so that the next call is throttled.
+	// facebook reports it on successful pages as well as on throttling errors.
+	for _, failed := range []bool{false, true} {
+		var srv *httptest.Server
+		numCalls := 0
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			numCalls++
+			w.Header().Add("Content-Type", "application/json")
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+			if page == 1 {
+				w.Header().Add("X-App-Usage", `{"call_count": 100, "total_time": 20, "total_cputime": 10}`)
+
+				if failed {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error": {"message": "Application request limit reached", "code": 4}}`))
+					return
+				}
+			}
+
+			_, _ = fmt.Fprintf(w, `{"data": [{"id": "%d"}], "paging": {"next": "%s/v3.0/items?page=%d"}}`, page, srv.URL, page+1)
+		}))
+
+		session := &Session{
+			Version:   "v3.0",
+			BaseURL:   srv.URL + "/",
+			Throttler: &Throttler{},
+		}
+
+		res, err := session.Get("/items", nil)
+
+		if err != nil {
+			t.Fatalf("cannot get first page. [failed:%v] [e:%v]", failed, err)
+		}
+
+		pr, _ := res.Paging(session)
+		var throttled *ThrottledError
+		var pageErr error
+
+		for _, err := range pr.Pages(PagingLimit{MaxPages: 5}) {
+			if err != nil {
+				pageErr = err
+				break
+			}
+		}
+
+		if failed {
+			var fbErr *Error
+
+			if !errors.As(pageErr, &fbErr) || fbErr.Code != 4 {
+				t.Fatalf("paging should fail with the error of the page. [e:%v]", pageErr)
+			}
+
+			_, pageErr = session.Get("/items", nil)
+		}
+
+		if !errors.As(pageErr, &throttled) || numCalls != 2 {
+			t.Fatalf("throttled call must not reach server. [failed:%v] [calls:%v] [e:%v]", failed, numCalls, pageErr)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestPagingPath(t *testing.T) {
+	cases := map[string]string{
+		"https://graph.facebook.com/v19.0/act_1/customaudiences?after=x": "/act_1/customaudiences",
+		"https://graph.facebook.com/act_1/adaccounts":                    "/act_1/adaccounts",
+		"https://graph.facebook.com/v3.0/me":                             "/me",
+	}
+
+	for url, expected := range cases {
+		if path := pagingPath(url); path != expected {
+			t.Fatalf("unexpected path. [url:%v] [expected:%v] [actual:%v]", url, expected, path)
+		}
+	}
+}
@@ -93,6 +93,10 @@ type RateLimiting struct {
 	TotalCPUTime                int    `json:"total_cputime"`                   // Percentage of the total CPU time that has been used.
 	Type                        string `json:"type"`                            // Type of rate limit logic being applied.
 	EstimatedTimeToRegainAccess int    `json:"estimated_time_to_regain_access"` // Time in minutes to resume calls.
+
+	// Fields only set in HTTP header X-Ad-Account-Usage.
+	AccIDUtilPCT      float64 `json:"acc_id_util_pct"`     // Percentage of calls made for this ad account.
+	ResetTimeDuration int     `json:"reset_time_duration"` // Time in seconds until the ad account usage is reset.
 }
 
 // AdsInsightsThrottle is the rate limiting header for Ads Insights API.
@@ -0,0 +1,249 @@
+// A facebook graph api client in go.
+// https://github.com/huandu/facebook/
+//
+// Copyright 2012, Huan Du
+// Licensed under the MIT license
+// https://github.com/huandu/facebook/blob/master/LICENSE
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy controls how a Session retries Graph API calls failing with a transient error.
+//
+// Throttling errors (codes 4, 17, 32, 341, 613 and the 80000 series) are retried for every method
+// as facebook rejects such calls before processing them. Other transient failures, e.g. errors with
+// is_transient set, codes 1 and 2, network errors and 5xx responses, are only retried for idempotent
+// methods unless RetryUnsafeMethods is set.
+//
+// A nil policy or a policy with MaxAttempts less than 2 disables retries.
+type RetryPolicy struct {
+	MaxAttempts int           // maximum number of attempts including the first one.
+	BaseDelay   time.Duration // delay before the first retry, doubled on every retry. defaults to 500ms.
+	MaxDelay    time.Duration // upper bound of a single delay. defaults to 30s.
+	Jitter      float64       // fraction of each delay to randomize, between 0 and 1.
+
+	// RetryUnsafeMethods allows retrying POST calls on failures which may happen
+	// after facebook has applied the call, e.g. network errors.
+	RetryUnsafeMethods bool
+
+	// Retryable overrides the default error classification if set.
+	Retryable func(method Method, err error) bool
+
+	// OnAttempt is called after every failed attempt.
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt reported to RetryPolicy.OnAttempt.
+type RetryAttempt struct {
+	Method    Method
+	Path      string
+	Attempt   int           // 1-based attempt number.
+	Err       error         // error returned by this attempt.
+	WillRetry bool          // whether another attempt follows.
+	Delay     time.Duration // wait before the next attempt.
+}
+
+// retry calls send until it succeeds or the session retry policy gives up.
+func (session *Session) retry(method Method, path string, params Params, send func() (*http.Response, error)) error {
+	policy := session.RetryPolicy
+	attempt := 0
+
+	for {
+		attempt++
+		response, err := send()
+
+		if err == nil {
+			return nil
+		}
+
+		if policy == nil || policy.MaxAttempts < 2 {
+			return err
+		}
+
+		willRetry := attempt < policy.MaxAttempts && !hasBinaryData(params) && session.Context().Err() == nil
+
+		if willRetry {
+			if policy.Retryable != nil {
+				willRetry = policy.Retryable(method, err)
+			} else {
+				willRetry = isRetryableError(method, err, response, policy.RetryUnsafeMethods)
+			}
+		}
+
+		var delay time.Duration
+
+		if willRetry {
+			delay = policy.backoff(attempt)
+
+			// facebook may tell us how long to wait. there is no point in retrying earlier,
+			// and it's better to give up than to wait longer than allowed by policy.
+			if hint := retryHint(response); hint > 0 {
+				if hint > policy.maxDelay() {
+					willRetry = false
+					delay = 0
+				} else if hint > delay {
+					delay = hint
+				}
+			}
+		}
+
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(RetryAttempt{
+				Method:    method,
+				Path:      path,
+				Attempt:   attempt,
+				Err:       err,
+				WillRetry: willRetry,
+				Delay:     delay,
+			})
+		}
+
+		if !willRetry {
+			return err
+		}
+
+		ctx := session.Context()
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("facebook: retry is aborted; %w; last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (policy *RetryPolicy) maxDelay() time.Duration {
+	if policy.MaxDelay > 0 {
+		return policy.MaxDelay
+	}
+
+	return defaultRetryMaxDelay
+}
+
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.BaseDelay
+
+	if delay <= 0 {
+		delay = defaultRetryBaseDelay
+	}
+
+	limit := policy.maxDelay()
+
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+
+	if delay > limit {
+		delay = limit
+	}
+
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+
+		if jitter > 1 {
+			jitter = 1
+		}
+
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// isRetryableError reports whether err is worth another attempt with default classification.
+func isRetryableError(method Method, err error, response *http.Response, unsafe bool) bool {
+	var fbErr *Error
+
+	if errors.As(err, &fbErr) {
+		if isThrottlingError(fbErr) {
+			return true
+		}
+
+		if !fbErr.IsTransient && fbErr.Code != 1 && fbErr.Code != 2 {
+			return false
+		}
+	} else {
+		var urlErr *url.Error
+
+		if !errors.As(err, &urlErr) && (response == nil || response.StatusCode < http.StatusInternalServerError) {
+			return false
+		}
+	}
+
+	return unsafe || isIdempotentMethod(method)
+}
+
+// isThrottlingError reports whether facebook rejects a call due to rate limiting.
+// See https://developers.facebook.com/docs/graph-api/overview/rate-limiting.
+func isThrottlingError(e *Error) bool {
+	switch e.Code {
+	case 4, 17, 32, 341, 613:
+		return true
+	}
+
+	return e.Code >= 80000 && e.Code < 80100
+}
+
+func isIdempotentMethod(method Method) bool {
+	return method == GET || method == PUT || method == DELETE
+}
+
+// hasBinaryData reports whether params contain a reader which cannot be sent twice.
+func hasBinaryData(params Params) bool {
+	for _, v := range params {
+		if _, ok := v.(*BinaryData); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryHint returns how long facebook asks to wait before calling again.
+func retryHint(response *http.Response) time.Duration {
+	if response == nil {
+		return 0
+	}
+
+	var hint time.Duration
+
+	if v := response.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			hint = time.Duration(seconds) * time.Second
+		} else if t, err := http.ParseTime(v); err == nil {
+			hint = time.Until(t)
+		}
+	}
+
+	info := parseUsageInfo(response.Header)
+
+	regain := max(info.App.EstimatedTimeToRegainAccess,
+		info.Page.EstimatedTimeToRegainAccess,
+		info.AdAccount.EstimatedTimeToRegainAccess)
+
+	for _, limits := range info.BusinessUseCase {
+		for _, limit := range limits {
+			if limit != nil {
+				regain = max(regain, limit.EstimatedTimeToRegainAccess)
+			}
+		}
+	}
+
+	return max(hint, time.Duration(regain)*time.Minute)
+}
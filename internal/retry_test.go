@@ -0,0 +1,161 @@
+// A facebook graph api client in go.
+// https://github.com/huandu/facebook/
+//
+// Copyright 2012, Huan Du
+// Licensed under the MIT license
+// https://github.com/huandu/facebook/blob/master/LICENSE
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSessionRetryThrottlingError(t *testing.T) {
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		w.Header().Add("Content-Type", "application/json")
+
+		if numCalls < 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"message": "Application request limit reached", "code": 4}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer srv.Close()
+
+	var attempts []RetryAttempt
+	session := &Session{
+		BaseURL: srv.URL + "/",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			OnAttempt: func(attempt RetryAttempt) {
+				attempts = append(attempts, attempt)
+			},
+		},
+	}
+
+	// throttled calls are rejected up front, so even POST is retried.
+	res, err := session.Post("/me/feed", nil)
+
+	if err != nil {
+		t.Fatalf("call should succeed after retries. [e:%v]", err)
+	}
+
+	if res.Get("id") == nil {
+		t.Fatalf("result should contain id. [result:%v]", res)
+	}
+
+	if numCalls != 3 {
+		t.Fatalf("server should be called 3 times. [calls:%v]", numCalls)
+	}
+
+	if len(attempts) != 2 || !attempts[0].WillRetry || attempts[1].Attempt != 2 {
+		t.Fatalf("hook should see 2 failed attempts. [attempts:%v]", attempts)
+	}
+}
+
+func TestSessionRetryIdempotency(t *testing.T) {
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error": {"message": "An unknown error occurred", "code": 1, "is_transient": true}}`))
+	}))
+	defer srv.Close()
+
+	session := &Session{
+		BaseURL: srv.URL + "/",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+
+	if _, err := session.Post("/me/feed", nil); err == nil {
+		t.Fatalf("call should fail.")
+	}
+
+	if numCalls != 1 {
+		t.Fatalf("POST must not be retried on transient errors by default. [calls:%v]", numCalls)
+	}
+
+	numCalls = 0
+
+	if _, err := session.Get("/me", nil); err == nil {
+		t.Fatalf("call should fail.")
+	}
+
+	if numCalls != 2 {
+		t.Fatalf("GET should be retried on transient errors. [calls:%v]", numCalls)
+	}
+}
+
+func TestSessionRetryHint(t *testing.T) {
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-App-Usage", `{"call_count": 100, "estimated_time_to_regain_access": 10}`)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"message": "Application request limit reached", "code": 4}}`))
+	}))
+	defer srv.Close()
+
+	session := &Session{
+		BaseURL: srv.URL + "/",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 3,
+			MaxDelay:    time.Second,
+		},
+	}
+
+	_, err := session.Get("/me", nil)
+	var fbErr *Error
+
+	if !errors.As(err, &fbErr) || fbErr.Code != 4 {
+		t.Fatalf("facebook error should be returned. [e:%v]", err)
+	}
+
+	if numCalls != 1 {
+		t.Fatalf("call should not be retried if regain access time exceeds max delay. [calls:%v]", numCalls)
+	}
+}
+
+func TestSessionRetryCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"message": "User request limit reached", "code": 17}}`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := (&Session{
+		BaseURL: srv.URL + "/",
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 5,
+			BaseDelay:   time.Hour,
+			MaxDelay:    time.Hour,
+			OnAttempt: func(attempt RetryAttempt) {
+				cancel()
+			},
+		},
+	}).WithContext(ctx)
+
+	_, err := session.Get("/me", nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("retry should be aborted by context. [e:%v]", err)
+	}
+}
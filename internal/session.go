@@ -46,16 +46,19 @@ var (
 
 	// checks whether it's a video post.
 	regexpIsVideoPost = regexp.MustCompile(`\/videos$`)
+	regexpVersion     = regexp.MustCompile(`^v\d+\.\d+$`)
 )
 
 // Session holds a facebook session with an access token.
 // Session should be created by App.Session or App.SessionFromSignedRequest.
 type Session struct {
 	HttpClient        HttpClient
-	Version           string // facebook versioning.
-	RFC3339Timestamps bool   // set to true to send date_format=Y-m-d\TH:i:sP on every request which will cause RFC3339 style timestamps to be returned
-	BaseURL           string // set to override API base URL - trailing slash is required, e.g. http://127.0.0.1:53453/
-	Instagram         bool   // set the session explicity to Instagram, see https://developers.facebook.com/docs/instagram-platform/instagram-api-with-instagram-login/migration-guide#step-2--update-your-code
+	Version           string       // facebook versioning.
+	RFC3339Timestamps bool         // set to true to send date_format=Y-m-d\TH:i:sP on every request which will cause RFC3339 style timestamps to be returned
+	BaseURL           string       // set to override API base URL - trailing slash is required, e.g. http://127.0.0.1:53453/
+	Instagram         bool         // set the session explicity to Instagram, see https://developers.facebook.com/docs/instagram-platform/instagram-api-with-instagram-login/migration-guide#step-2--update-your-code
+	RetryPolicy       *RetryPolicy // set to retry calls failing with transient errors. nil disables retries.
+	Throttler         *Throttler   // set to delay calls as rate limit usage approaches 100%. nil disables throttling.
 
 	accessToken string // facebook access token. can be empty.
 	app         *App
@@ -399,28 +402,96 @@ func (session *Session) graph(path string, method Method, params Params) (res Re
 		graphURL = session.getURL("graph", path, urlParams)
 	}
 
-	var response *http.Response
+	if method != GET && method != POST {
+		params["method"] = method
+	}
 
-	if method == GET {
-		response, err = session.sendGetRequest(graphURL, &res)
-	} else {
-		if method != POST {
-			params["method"] = method
+	err = session.retry(method, path, params, func() (response *http.Response, err error) {
+		res = nil
+
+		if err = session.throttle(path); err != nil {
+			return
 		}
 
-		response, err = session.sendPostRequest(graphURL, params, &res)
-	}
+		if method == GET {
+			response, err = session.sendGetRequest(graphURL, &res)
+		} else {
+			response, err = session.sendPostRequest(graphURL, params, &res)
+		}
+
+		if response != nil {
+			session.addDebugInfo(res, response)
+			session.addUsageInfo(res, response)
+			session.observeUsage(path, response)
+		}
+
+		if res != nil {
+			err = res.Err()
+		}
+
+		return
+	})
+
+	return
+}
+
+// getPage gets a page at pagingURL, a full URL returned by facebook in the paging field.
+// Like graph, it is throttled and retried according to the session's throttler and retry policy.
+func (session *Session) getPage(pagingURL string) (res Result, err error) {
+	path := pagingPath(pagingURL)
+
+	err = session.retry(GET, path, nil, func() (response *http.Response, err error) {
+		res = nil
 
-	if response != nil {
-		session.addDebugInfo(res, response)
-		session.addUsageInfo(res, response)
+		if err = session.throttle(path); err != nil {
+			return
+		}
+
+		request, err := http.NewRequest("GET", pagingURL, nil)
+
+		if err != nil {
+			return
+		}
+
+		var data []byte
+		response, data, err = session.sendRequest(request)
+
+		if err == nil {
+			res, err = MakeResult(data)
+		}
+
+		// usage is observed on errors too, as throttling errors come with the usage which caused them.
+		if response != nil {
+			session.addDebugInfo(res, response)
+			session.addUsageInfo(res, response)
+			session.observeUsage(path, response)
+		}
+
+		if res != nil {
+			err = res.Err()
+		}
+
+		return
+	})
+
+	return
+}
+
+// pagingPath returns the graph path of a paging URL without the version, e.g. "/act_1/customaudiences".
+func pagingPath(pagingURL string) string {
+	u, err := url.Parse(pagingURL)
+
+	if err != nil {
+		return ""
 	}
 
-	if res != nil {
-		err = res.Err()
+	path := u.Path
+
+	if version, rest, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/"); ok && regexpVersion.MatchString(version) {
+		path = "/" + rest
 	}
 
-	return
+	return path
 }
 
 func (session *Session) graphBatch(batchParams Params, params ...Params) ([]Result, error) {
@@ -433,7 +504,17 @@ func (session *Session) graphBatch(batchParams Params, params ...Params) ([]Resu
 
 	var res []Result
 	graphURL := session.getURL("graph", "", nil)
-	_, err := session.sendPostRequest(graphURL, batchParams, &res)
+	err := session.retry(POST, "", batchParams, func() (*http.Response, error) {
+		res = nil
+
+		if err := session.throttle(""); err != nil {
+			return nil, err
+		}
+
+		response, err := session.sendPostRequest(graphURL, batchParams, &res)
+		session.observeUsage("", response)
+		return response, err
+	})
 	return res, err
 }
 
@@ -658,8 +739,12 @@ func (session *Session) addUsageInfo(res Result, response *http.Response) Result
 		return res
 	}
 
+	res[usageInfoKey] = parseUsageInfo(response.Header)
+	return res
+}
+
+func parseUsageInfo(header http.Header) *UsageInfo {
 	var usageInfo UsageInfo
-	header := response.Header
 
 	if usage := header.Get("X-App-Usage"); usage != "" {
 		_ = json.Unmarshal([]byte(usage), &usageInfo.App)
@@ -681,8 +766,23 @@ func (session *Session) addUsageInfo(res Result, response *http.Response) Result
 		_ = json.Unmarshal([]byte(usage), &usageInfo.AdsInsights)
 	}
 
-	res[usageInfoKey] = &usageInfo
-	return res
+	return &usageInfo
+}
+
+func (session *Session) throttle(path string) error {
+	if session.Throttler == nil {
+		return nil
+	}
+
+	return session.Throttler.wait(session.Context(), path)
+}
+
+func (session *Session) observeUsage(path string, response *http.Response) {
+	if session.Throttler == nil || response == nil {
+		return
+	}
+
+	session.Throttler.observe(path, parseUsageInfo(response.Header))
 }
 
 // Context returns the session's context.
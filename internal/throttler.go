@@ -0,0 +1,236 @@
+// A facebook graph api client in go.
+// https://github.com/huandu/facebook/
+//
+// Copyright 2012, Huan Du
+// Licensed under the MIT license
+// https://github.com/huandu/facebook/blob/master/LICENSE
+
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultThrottleThreshold = 75
+	defaultThrottleMaxDelay  = 10 * time.Second
+	defaultThrottleTTL       = 5 * time.Minute
+
+	throttleAppKey             = "app"
+	throttleAdAccountKeyPrefix = "ad_account:"
+	throttleBusinessKeyPrefix  = "business_use_case:"
+)
+
+// Throttler slows down calls as the rate limit usage reported by facebook approaches 100%.
+//
+// Usage is tracked per app, per ad account and per business use case object ID
+// from the X-App-Usage, X-Ad-Account-Usage, X-Business-Use-Case-Usage and X-Fb-Ads-Insights-Throttle
+// headers. Once usage crosses Threshold, calls are delayed by up to MaxDelay. Calls hitting a bucket
+// which is used up are held back until facebook says access is regained, or rejected with
+// a *ThrottledError if that takes longer than MaxWait.
+//
+// A Throttler is safe for concurrent use. Share one Throttler among all sessions of an app
+// so that they see each other's usage. The zero value is ready to use.
+type Throttler struct {
+	Threshold float64       // usage percentage from which calls are delayed. defaults to 75.
+	MaxDelay  time.Duration // delay applied right before usage reaches 100%. defaults to 10s.
+	MaxWait   time.Duration // longest wait for a used up bucket before rejecting a call. 0 rejects at once.
+	TTL       time.Duration // how long a usage reading is trusted. defaults to 5m.
+
+	mu      sync.Mutex
+	buckets map[string]*throttleBucket
+}
+
+type throttleBucket struct {
+	usage        float64
+	observedAt   time.Time
+	blockedUntil time.Time
+}
+
+// Usage returns last known usage percentage of every tracked bucket.
+// Keys are "app", "ad_account:{id}" and "business_use_case:{id}".
+func (t *Throttler) Usage() map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	usage := make(map[string]float64, len(t.buckets))
+
+	for key, b := range t.buckets {
+		if now.Sub(b.observedAt) <= t.ttl() {
+			usage[key] = b.usage
+		}
+	}
+
+	return usage
+}
+
+// wait blocks until a call to path is allowed or ctx is done.
+func (t *Throttler) wait(ctx context.Context, path string) error {
+	delay, err := t.delay(path, time.Now())
+
+	if err != nil || delay <= 0 {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *Throttler) delay(path string, now time.Time) (time.Duration, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	threshold := t.Threshold
+
+	if threshold <= 0 || threshold >= 100 {
+		threshold = defaultThrottleThreshold
+	}
+
+	maxDelay := t.MaxDelay
+
+	if maxDelay <= 0 {
+		maxDelay = defaultThrottleMaxDelay
+	}
+
+	var delay time.Duration
+
+	for _, key := range throttleKeys(path) {
+		b, ok := t.buckets[key]
+
+		if !ok || now.Sub(b.observedAt) > t.ttl() {
+			continue
+		}
+
+		var d time.Duration
+
+		if b.usage >= 100 || b.blockedUntil.After(now) {
+			retryAfter := b.blockedUntil.Sub(now)
+
+			if retryAfter <= 0 || retryAfter > t.MaxWait {
+				return 0, &ThrottledError{
+					Key:        key,
+					Usage:      b.usage,
+					RetryAfter: max(retryAfter, 0),
+				}
+			}
+
+			d = retryAfter
+		} else if b.usage >= threshold {
+			d = time.Duration(float64(maxDelay) * (b.usage - threshold) / (100 - threshold))
+		}
+
+		delay = max(delay, d)
+	}
+
+	return delay, nil
+}
+
+// observe records usage info returned by facebook for a call to path.
+func (t *Throttler) observe(path string, info *UsageInfo) {
+	if info == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.record(throttleAppKey, info.App, info.AdsInsights.AppIDUtilPCT, now)
+
+	if id, isAdAccount := throttleObjectID(path); isAdAccount {
+		t.record(throttleAdAccountKeyPrefix+id, info.AdAccount, info.AdsInsights.AccIDUtilPCT, now)
+	}
+
+	for id, limits := range info.BusinessUseCase {
+		var merged RateLimiting
+
+		for _, limit := range limits {
+			if limit == nil {
+				continue
+			}
+
+			merged.CallCount = max(merged.CallCount, limit.CallCount)
+			merged.TotalTime = max(merged.TotalTime, limit.TotalTime)
+			merged.TotalCPUTime = max(merged.TotalCPUTime, limit.TotalCPUTime)
+			merged.EstimatedTimeToRegainAccess = max(merged.EstimatedTimeToRegainAccess, limit.EstimatedTimeToRegainAccess)
+		}
+
+		t.record(throttleBusinessKeyPrefix+id, merged, 0, now)
+	}
+}
+
+func (t *Throttler) record(key string, limit RateLimiting, extra float64, now time.Time) {
+	// facebook omits the header if there is nothing to report.
+	if limit == (RateLimiting{}) && extra == 0 {
+		return
+	}
+
+	usage := max(float64(limit.CallCount), float64(limit.TotalTime), float64(limit.TotalCPUTime), limit.AccIDUtilPCT, extra)
+	b := &throttleBucket{
+		usage:      usage,
+		observedAt: now,
+	}
+
+	if limit.EstimatedTimeToRegainAccess > 0 {
+		b.blockedUntil = now.Add(time.Duration(limit.EstimatedTimeToRegainAccess) * time.Minute)
+	} else if usage >= 100 && limit.ResetTimeDuration > 0 {
+		b.blockedUntil = now.Add(time.Duration(limit.ResetTimeDuration) * time.Second)
+	}
+
+	if t.buckets == nil {
+		t.buckets = map[string]*throttleBucket{}
+	}
+
+	t.buckets[key] = b
+}
+
+func (t *Throttler) ttl() time.Duration {
+	if t.TTL > 0 {
+		return t.TTL
+	}
+
+	return defaultThrottleTTL
+}
+
+// throttleKeys returns all buckets a call to path is counted against.
+func throttleKeys(path string) []string {
+	keys := []string{throttleAppKey}
+	id, isAdAccount := throttleObjectID(path)
+
+	if id == "" {
+		return keys
+	}
+
+	if isAdAccount {
+		keys = append(keys, throttleAdAccountKeyPrefix+id)
+	}
+
+	return append(keys, throttleBusinessKeyPrefix+id)
+}
+
+// throttleObjectID returns the graph object ID in the first segment of path.
+// The "act_" prefix of ad account IDs is stripped as business use case usage is keyed by bare IDs.
+func throttleObjectID(path string) (id string, isAdAccount bool) {
+	id = strings.TrimPrefix(path, "/")
+
+	if i := strings.IndexByte(id, '/'); i >= 0 {
+		id = id[:i]
+	}
+
+	if strings.HasPrefix(id, "act_") {
+		return id[len("act_"):], true
+	}
+
+	return id, false
+}
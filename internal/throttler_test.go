@@ -0,0 +1,101 @@
+// A facebook graph api client in go.
+// https://github.com/huandu/facebook/
+//
+// Copyright 2012, Huan Du
+// Licensed under the MIT license
+// https://github.com/huandu/facebook/blob/master/LICENSE
+
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThrottlerDelay(t *testing.T) {
+	throttler := &Throttler{
+		Threshold: 50,
+		MaxDelay:  10 * time.Second,
+	}
+	now := time.Now()
+
+	throttler.observe("/act_123/customaudiences", &UsageInfo{
+		App:       RateLimiting{CallCount: 10},
+		AdAccount: RateLimiting{AccIDUtilPCT: 75},
+		BusinessUseCase: BusinessUseCaseUsage{
+			"456": []*RateLimiting{{CallCount: 20}, {TotalTime: 40}},
+		},
+	})
+
+	usage := throttler.Usage()
+
+	if usage["app"] != 10 || usage["ad_account:123"] != 75 || usage["business_use_case:456"] != 40 {
+		t.Fatalf("unexpected usage. [usage:%v]", usage)
+	}
+
+	if delay, err := throttler.delay("/me", now); err != nil || delay != 0 {
+		t.Fatalf("calls below threshold must not be delayed. [delay:%v] [e:%v]", delay, err)
+	}
+
+	if delay, err := throttler.delay("/act_123/ads", now); err != nil || delay != 5*time.Second {
+		t.Fatalf("ad account calls should be delayed. [delay:%v] [e:%v]", delay, err)
+	}
+}
+
+func TestThrottlerReject(t *testing.T) {
+	throttler := &Throttler{
+		MaxWait: time.Minute,
+	}
+
+	throttler.observe("/act_123/ads", &UsageInfo{
+		AdAccount: RateLimiting{CallCount: 100, EstimatedTimeToRegainAccess: 5},
+	})
+
+	_, err := throttler.delay("/act_123/ads", time.Now())
+	var throttled *ThrottledError
+
+	if !errors.As(err, &throttled) || throttled.Key != "ad_account:123" {
+		t.Fatalf("call should be rejected. [e:%v]", err)
+	}
+
+	if throttled.RetryAfter <= 4*time.Minute {
+		t.Fatalf("retry after should come from estimated time to regain access. [retry_after:%v]", throttled.RetryAfter)
+	}
+
+	if delay, err := throttler.delay("/act_456/ads", time.Now()); err != nil || delay != 0 {
+		t.Fatalf("other ad accounts must not be throttled. [delay:%v] [e:%v]", delay, err)
+	}
+}
+
+func TestSessionThrottler(t *testing.T) {
+	numCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-App-Usage", `{"call_count": 100, "total_time": 20, "total_cputime": 10}`)
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer srv.Close()
+
+	throttler := &Throttler{}
+	session1 := &Session{BaseURL: srv.URL + "/", Throttler: throttler}
+	session2 := &Session{BaseURL: srv.URL + "/", Throttler: throttler}
+
+	if _, err := session1.Get("/me", nil); err != nil {
+		t.Fatalf("first call should succeed. [e:%v]", err)
+	}
+
+	_, err := session2.Get("/me", nil)
+	var throttled *ThrottledError
+
+	if !errors.As(err, &throttled) || throttled.Key != "app" {
+		t.Fatalf("app usage should be shared among sessions. [e:%v]", err)
+	}
+
+	if numCalls != 1 {
+		t.Fatalf("throttled call must not reach server. [calls:%v]", numCalls)
+	}
+}
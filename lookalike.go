@@ -0,0 +1,128 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxIDsPerRequest is the maximum number of objects facebook reads in a single ?ids= request.
+const maxIDsPerRequest = 50
+
+type LookalikeType = string
+
+// Types of lookalike audiences.
+const (
+	SimilarityLookalikeType  LookalikeType = "similarity"
+	ReachLookalikeType       LookalikeType = "reach"
+	CustomRatioLookalikeType LookalikeType = "custom_ratio"
+)
+
+// LookalikeSpec describes how a lookalike audience is derived from its seed.
+// See https://developers.facebook.com/docs/marketing-api/audiences/guides/lookalike-audiences.
+type LookalikeSpec struct {
+	Type          LookalikeType          `json:"type,omitempty"`
+	Ratio         float64                `json:"ratio,omitempty"`          // share of the population, from 0.01 to 0.20.
+	StartingRatio float64                `json:"starting_ratio,omitempty"` // excludes the most similar share, to build tiers.
+	Country       string                 `json:"country,omitempty"`        // 2-letter country code. either Country or LocationSpec is set.
+	LocationSpec  *LookalikeLocationSpec `json:"location_spec,omitempty"`
+	Origin        []LookalikeOrigin      `json:"origin,omitempty"` // set by facebook.
+}
+
+// LookalikeLocationSpec selects the countries of a multi-country lookalike audience.
+type LookalikeLocationSpec struct {
+	GeoLocations LookalikeGeoLocations `json:"geo_locations"`
+}
+
+type LookalikeGeoLocations struct {
+	Countries     []string `json:"countries,omitempty"`
+	CountryGroups []string `json:"country_groups,omitempty"`
+}
+
+// LookalikeOrigin is a seed of a lookalike audience.
+type LookalikeOrigin struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CreateLookalikeRequest holds the fields of a new lookalike audience.
+type CreateLookalikeRequest struct {
+	Name             string
+	Description      string
+	OriginAudienceID string // seed audience.
+	Spec             LookalikeSpec
+}
+
+func (r CreateLookalikeRequest) validate() error {
+	if r.OriginAudienceID == "" {
+		return fmt.Errorf("facebook: origin audience of lookalike is not set")
+	}
+
+	// similarity and reach lookalikes may be sized by facebook.
+	if r.Spec.Ratio != 0 || r.Spec.Type == "" || r.Spec.Type == CustomRatioLookalikeType {
+		if r.Spec.Ratio < 0.01 || r.Spec.Ratio > 0.2 {
+			return fmt.Errorf("facebook: lookalike ratio %v is not between 0.01 and 0.20", r.Spec.Ratio)
+		}
+
+		if r.Spec.StartingRatio < 0 || r.Spec.StartingRatio >= r.Spec.Ratio {
+			return fmt.Errorf("facebook: lookalike starting ratio %v is not below ratio %v", r.Spec.StartingRatio, r.Spec.Ratio)
+		}
+	}
+
+	if (r.Spec.Country == "") == (r.Spec.LocationSpec == nil) {
+		return fmt.Errorf("facebook: lookalike must set either country or location spec")
+	}
+
+	return nil
+}
+
+// CreateLookalikeAudience creates a lookalike audience with CreateAudience and returns its ID.
+// Facebook populates the audience asynchronously, see CustomAudienceInfo.Populated.
+func (c *Client) CreateLookalikeAudience(ctx context.Context, adAccountID string, request CreateLookalikeRequest) (string, error) {
+	if err := request.validate(); err != nil {
+		return "", err
+	}
+
+	return c.CreateAudienceTyped(ctx, adAccountID, CreateAudienceRequest{
+		Name:        request.Name,
+		Description: request.Description,
+		Subtype:     LookalikeAudienceSubtype,
+		Params: Params{
+			"origin_audience_id": request.OriginAudienceID,
+			"lookalike_spec":     request.Spec,
+		},
+	})
+}
+
+// LookalikeAudiences returns the lookalike audiences using seedAudienceID as seed.
+func (c *Client) LookalikeAudiences(ctx context.Context, seedAudienceID string) ([]CustomAudienceInfo, error) {
+	seed, err := c.AudienceTyped(ctx, seedAudienceID, FieldsParams("id", "lookalike_audience_ids"))
+	if err != nil {
+		return nil, err
+	}
+
+	audiences := make([]CustomAudienceInfo, 0, len(seed.LookalikeAudienceIDs))
+
+	for ids := range slices.Chunk(seed.LookalikeAudienceIDs, maxIDsPerRequest) {
+		params := FieldsParams(customAudienceFields...)
+		params["ids"] = strings.Join(ids, ",")
+
+		res, err := c.session.WithContext(ctx).Get("/", params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			audience, err := DecodeFieldAs[CustomAudienceInfo](res, id)
+			if err != nil {
+				return nil, err
+			}
+
+			audiences = append(audiences, audience)
+		}
+	}
+
+	return audiences, nil
+}
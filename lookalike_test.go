@@ -0,0 +1,34 @@
+package facebook
+
+import "testing"
+
+func TestCreateLookalikeRequestValidate(t *testing.T) {
+	location := &LookalikeLocationSpec{GeoLocations: LookalikeGeoLocations{Countries: []string{"US", "DK"}}}
+
+	cases := []struct {
+		name    string
+		request CreateLookalikeRequest
+		valid   bool
+	}{
+		{name: "ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.01, Country: "US"}}, valid: true},
+		{name: "tier", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: CustomRatioLookalikeType, Ratio: 0.2, StartingRatio: 0.1, LocationSpec: location}}, valid: true},
+		{name: "similarity without ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: SimilarityLookalikeType, Country: "US"}}, valid: true},
+		{name: "reach without ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: ReachLookalikeType, Country: "US"}}, valid: true},
+		{name: "reach with ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: ReachLookalikeType, Ratio: 0.05, Country: "US"}}, valid: true},
+		{name: "no origin", request: CreateLookalikeRequest{Spec: LookalikeSpec{Ratio: 0.01, Country: "US"}}},
+		{name: "no ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Country: "US"}}},
+		{name: "custom ratio without ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: CustomRatioLookalikeType, Country: "US"}}},
+		{name: "ratio too large", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.25, Country: "US"}}},
+		{name: "similarity with ratio too small", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Type: SimilarityLookalikeType, Ratio: 0.001, Country: "US"}}},
+		{name: "starting ratio above ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.05, StartingRatio: 0.05, Country: "US"}}},
+		{name: "negative starting ratio", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.05, StartingRatio: -0.01, Country: "US"}}},
+		{name: "no location", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.01}}},
+		{name: "country and location spec", request: CreateLookalikeRequest{OriginAudienceID: "1", Spec: LookalikeSpec{Ratio: 0.01, Country: "US", LocationSpec: location}}},
+	}
+
+	for _, c := range cases {
+		if err := c.request.validate(); (err == nil) != c.valid {
+			t.Fatalf("unexpected validation. [case:%v] [valid:%v] [e:%v]", c.name, c.valid, err)
+		}
+	}
+}
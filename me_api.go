@@ -9,18 +9,36 @@ type User struct {
 	Email string `json:"email"`
 }
 
+type AdAccount struct {
+	ID            string `facebook:"id,required" json:"id"`
+	AccountID     string `json:"account_id"`
+	Name          string `json:"name"`
+	AccountStatus int    `json:"account_status"`
+	Currency      string `json:"currency"`
+	TimezoneName  string `json:"timezone_name"`
+}
+
+var adAccountFields = []string{"id", "account_id", "name", "account_status", "currency", "timezone_name"}
+
 type MeAPI interface {
 	AdAccounts(ctx context.Context, params Params) (Result, error)
+	AdAccountsTyped(ctx context.Context, params Params) ([]AdAccount, error)
 	Me(ctx context.Context, params Params) (Result, error)
 	User(ctx context.Context) (User, error)
 }
 
 func (c *Client) AdAccounts(ctx context.Context, params Params) (Result, error) {
-	return c.session.Get("/me/adaccounts", params)
+	return c.session.WithContext(ctx).Get("/me/adaccounts", params)
+}
+
+// AdAccountsTyped is AdAccounts decoded into AdAccount values.
+// Only the first page is returned, use Paging and All to read all ad accounts.
+func (c *Client) AdAccountsTyped(ctx context.Context, params Params) ([]AdAccount, error) {
+	return decodeData[AdAccount](c.AdAccounts(ctx, withDefaultFields(params, adAccountFields...)))
 }
 
 func (c *Client) Me(ctx context.Context, params Params) (Result, error) {
-	res, err := c.session.Get("/me", params)
+	res, err := c.session.WithContext(ctx).Get("/me", params)
 	if err != nil {
 		return Result{}, err
 	}
@@ -28,17 +46,11 @@ func (c *Client) Me(ctx context.Context, params Params) (Result, error) {
 	return res, nil
 }
 
-func (c *Client) User(_ context.Context) (User, error) {
-	res, err := c.Me(context.Background(), FieldsParams("id", "email"))
-	if err != nil {
-		return User{}, err
-	}
-
-	var user User
-	err = res.Decode(&user)
+func (c *Client) User(ctx context.Context) (User, error) {
+	res, err := c.Me(ctx, FieldsParams("id", "email"))
 	if err != nil {
 		return User{}, err
 	}
 
-	return user, nil
+	return DecodeAs[User](res)
 }
@@ -0,0 +1,115 @@
+package facebook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// sha256Pattern matches values which are already hashed, so that they are not hashed twice.
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// zipPlus4Pattern matches US ZIP+4 codes.
+var zipPlus4Pattern = regexp.MustCompile(`^[0-9]{5}-[0-9]{4}$`)
+
+// hashValue normalizes v and hashes it with SHA-256.
+// Already hashed values are kept as is, and empty values stay empty.
+func hashValue(v string, normalize func(string) string) string {
+	if hashed := strings.ToLower(strings.TrimSpace(v)); sha256Pattern.MatchString(hashed) {
+		return hashed
+	}
+
+	v = normalize(v)
+
+	if v == "" {
+		return ""
+	}
+
+	return sha256Hex(v)
+}
+
+func sha256Hex(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])
+}
+
+// The normalize functions follow the rules documented at
+// https://developers.facebook.com/docs/marketing-api/conversions-api/parameters/customer-information-parameters
+// and https://developers.facebook.com/docs/marketing-api/audiences/guides/custom-audiences#hash.
+
+func normalizeEmail(v string) string {
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+// normalizePhone keeps digits only and removes leading zeros. The country code must be included.
+func normalizePhone(v string) string {
+	return strings.TrimLeft(keepRunes(v, unicode.IsDigit), "0")
+}
+
+// normalizeName keeps lowercase letters only, e.g. "Mary-Ann" becomes "maryann".
+func normalizeName(v string) string {
+	return keepRunes(strings.ToLower(v), unicode.IsLetter)
+}
+
+// normalizeCity keeps lowercase letters only, e.g. "New York" becomes "newyork".
+func normalizeCity(v string) string {
+	return keepRunes(strings.ToLower(v), unicode.IsLetter)
+}
+
+// normalizeState keeps lowercase letters only of a 2-letter state code.
+func normalizeState(v string) string {
+	return keepRunes(strings.ToLower(v), unicode.IsLetter)
+}
+
+// normalizeZip lowercases and removes spaces and dashes. US ZIP+4 codes keep their first 5 digits only,
+// e.g. "94025-1234" becomes "94025", and "00-950" becomes "00950".
+func normalizeZip(v string) string {
+	v = keepRunes(strings.ToLower(v), func(r rune) bool { return !unicode.IsSpace(r) })
+
+	if zipPlus4Pattern.MatchString(v) {
+		return v[:5]
+	}
+
+	return strings.ReplaceAll(v, "-", "")
+}
+
+// normalizeCountry keeps lowercase letters only of a 2-letter ISO 3166-1 alpha-2 code.
+func normalizeCountry(v string) string {
+	return keepRunes(strings.ToLower(v), unicode.IsLetter)
+}
+
+// normalizeGender returns "f" or "m" from the first letter of v, or an empty string.
+func normalizeGender(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+
+	if strings.HasPrefix(v, "f") {
+		return "f"
+	}
+
+	if strings.HasPrefix(v, "m") {
+		return "m"
+	}
+
+	return ""
+}
+
+// normalizeDate keeps digits only of a YYYYMMDD date, e.g. "1990-01-31" becomes "19900131".
+func normalizeDate(v string) string {
+	return keepRunes(v, unicode.IsDigit)
+}
+
+func normalizeExternalID(v string) string {
+	return strings.TrimSpace(v)
+}
+
+func keepRunes(v string, keep func(r rune) bool) string {
+	return strings.Map(func(r rune) rune {
+		if keep(r) {
+			return r
+		}
+
+		return -1
+	}, v)
+}
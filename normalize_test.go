@@ -0,0 +1,155 @@
+package facebook
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name      string
+		normalize func(string) string
+		values    map[string]string
+	}{
+		{
+			name:      "email",
+			normalize: normalizeEmail,
+			values: map[string]string{
+				" John.Doe@Example.COM ": "john.doe@example.com",
+				"":                       "",
+			},
+		},
+		{
+			name:      "phone",
+			normalize: normalizePhone,
+			values: map[string]string{
+				"+1 (650) 555-1212":  "16505551212",
+				"0033 6 12 34 56 78": "33612345678",
+				"00441234567890":     "441234567890",
+				"abc":                "",
+			},
+		},
+		{
+			name:      "name",
+			normalize: normalizeName,
+			values: map[string]string{
+				"Mary-Ann":   "maryann",
+				"O'Brien":    "obrien",
+				" Jean Luc.": "jeanluc",
+				"Émilie":     "émilie",
+			},
+		},
+		{
+			name:      "city",
+			normalize: normalizeCity,
+			values: map[string]string{
+				"New York":         "newyork",
+				"Saint-Étienne":    "saintétienne",
+				"Washington, D.C.": "washingtondc",
+			},
+		},
+		{
+			name:      "state",
+			normalize: normalizeState,
+			values: map[string]string{
+				"CA":   "ca",
+				" ny ": "ny",
+			},
+		},
+		{
+			name:      "zip",
+			normalize: normalizeZip,
+			values: map[string]string{
+				"94025":      "94025",
+				"94025-1234": "94025",
+				" 94025 ":    "94025",
+				"00-950":     "00950",
+				"1000-001":   "1000001",
+				"SW1A 1AA":   "sw1a1aa",
+				"123456-789": "123456789",
+			},
+		},
+		{
+			name:      "country",
+			normalize: normalizeCountry,
+			values: map[string]string{
+				"US":   "us",
+				" dk ": "dk",
+			},
+		},
+		{
+			name:      "gender",
+			normalize: normalizeGender,
+			values: map[string]string{
+				"Female": "f",
+				"M":      "m",
+				" male ": "m",
+				"other":  "",
+			},
+		},
+		{
+			name:      "date",
+			normalize: normalizeDate,
+			values: map[string]string{
+				"1990-01-31": "19900131",
+				"19900131":   "19900131",
+				"01":         "01",
+			},
+		},
+		{
+			name:      "external ID",
+			normalize: normalizeExternalID,
+			values: map[string]string{
+				" User-42 ": "User-42",
+			},
+		},
+		{
+			name:      "first initial",
+			normalize: normalizeFirstInitial,
+			values: map[string]string{
+				"John":   "j",
+				"'Émile": "é",
+				"":       "",
+			},
+		},
+		{
+			name:      "mobile advertiser ID",
+			normalize: normalizeMobileAdvertiserID,
+			values: map[string]string{
+				" AB12CD34-5678-90EF-AB12-CD3456789012 ": "ab12cd34-5678-90ef-ab12-cd3456789012",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		for v, expected := range c.values {
+			if actual := c.normalize(v); actual != expected {
+				t.Fatalf("unexpected normalized value. [field:%v] [value:%q] [expected:%q] [actual:%q]", c.name, v, expected, actual)
+			}
+		}
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	hashed := sha256Hex("john.doe@example.com")
+
+	cases := map[string]string{
+		"John.Doe@Example.com ": hashed,
+		hashed:                  hashed,
+		strings.ToUpper(hashed): hashed,
+		" " + hashed + " ":      hashed,
+		"":                      "",
+		"   ":                   "",
+		hashed[:63]:             sha256Hex(hashed[:63]),
+	}
+
+	for v, expected := range cases {
+		if actual := hashValue(v, normalizeEmail); actual != expected {
+			t.Fatalf("unexpected hash. [value:%q] [expected:%v] [actual:%v]", v, expected, actual)
+		}
+	}
+
+	if hashed != "836f82db99121b3481011f16b49dfa5fbc714a0d1b1b9f784a1ebbbf5b39577f" {
+		t.Fatalf("sha256 hex of email changed. [hash:%v]", hashed)
+	}
+}
@@ -0,0 +1,123 @@
+package facebook
+
+import (
+	"reflect"
+	"time"
+)
+
+// CRMEventSource is the event source of events sent from a CRM, see CRMLeadEvent.
+const CRMEventSource = "crm"
+
+// OfflineEvent is a conversion which happened offline, e.g. a purchase in a physical store.
+// Facebook accepts offline events up to MaxPhysicalStoreEventAge old.
+// See https://developers.facebook.com/docs/marketing-api/conversions-api/offline-events.
+type OfflineEvent struct {
+	EventName string // e.g. "Purchase".
+	EventTime time.Time
+	EventID   string // identifies the event, so that facebook ignores it when sent again.
+	UserData  UserData
+
+	Value      float64
+	Currency   string
+	OrderID    string
+	Contents   []Content
+	CustomData *CustomData // other business data. fields above take precedence.
+}
+
+// ServerEvent returns the event with the "physical_store" action source.
+func (e OfflineEvent) ServerEvent() ServerEvent {
+	var data CustomData
+	if e.CustomData != nil {
+		data = *e.CustomData
+	}
+
+	if e.Value != 0 {
+		data.Value = e.Value
+	}
+
+	if e.Currency != "" {
+		data.Currency = e.Currency
+	}
+
+	if e.OrderID != "" {
+		data.OrderID = e.OrderID
+	}
+
+	if len(e.Contents) > 0 {
+		data.Contents = e.Contents
+	}
+
+	event := ServerEvent{
+		EventName:    e.EventName,
+		EventTime:    e.EventTime,
+		EventID:      e.EventID,
+		ActionSource: ActionSourcePhysicalStore,
+		UserData:     e.UserData,
+	}
+
+	if !reflect.ValueOf(data).IsZero() {
+		event.CustomData = &data
+	}
+
+	return event
+}
+
+// CRMLeadEvent reports that a lead reached a stage in a CRM, so that facebook optimizes ads for leads
+// which convert. See https://developers.facebook.com/docs/marketing-api/conversions-api/conversion-leads-integration.
+type CRMLeadEvent struct {
+	Stage           string // stage the lead reached, sent as the event name, e.g. "Qualified".
+	EventTime       time.Time
+	EventID         string   // identifies the event, so that facebook ignores it when sent again.
+	LeadID          string   // ID of the lead generated by Lead Ads, 15 to 17 digits.
+	LeadEventSource string   // name of the CRM, e.g. "Salesforce".
+	UserData        UserData // customer information matching leads without LeadID. LeadID takes precedence over UserData.LeadID.
+	Custom          map[string]interface{}
+}
+
+// ServerEvent returns the event with the "system_generated" action source and the "crm" event source.
+func (e CRMLeadEvent) ServerEvent() ServerEvent {
+	user := e.UserData
+	if e.LeadID != "" {
+		user.LeadID = e.LeadID
+	}
+
+	return ServerEvent{
+		EventName:    e.Stage,
+		EventTime:    e.EventTime,
+		EventID:      e.EventID,
+		ActionSource: ActionSourceSystemGenerated,
+		UserData:     user,
+		CustomData: &CustomData{
+			EventSource:     CRMEventSource,
+			LeadEventSource: e.LeadEventSource,
+			Custom:          e.Custom,
+		},
+	}
+}
+
+// EventUpload identifies an upload of offline or CRM events in Events Manager.
+// Pass its params with every call of the upload, e.g. with UploadEventsOptions.Upload.
+type EventUpload struct {
+	Tag         string // upload_tag, e.g. "store_purchases_2024_05".
+	ID          string // upload_id, unique per upload.
+	Source      string // upload_source, e.g. "salesforce".
+	NamespaceID string // namespace_id, scoping external IDs of the events.
+}
+
+// Params returns the params of the upload which are set.
+func (u EventUpload) Params() Params {
+	params := make(Params)
+
+	for k, v := range map[string]string{
+		"upload_tag":    u.Tag,
+		"upload_id":     u.ID,
+		"upload_source": u.Source,
+		"namespace_id":  u.NamespaceID,
+	} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,118 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// goldenCRMLeadEvent is the event of a CRMLeadEvent, as facebook expects it. LeadID takes precedence over UserData.LeadID.
+const goldenCRMLeadEvent = `{
+	"event_name": "Qualified",
+	"event_id": "lead-1-qualified",
+	"action_source": "system_generated",
+	"user_data": {
+		"client_ip_address": "192.0.2.1",
+		"lead_id": "1234567890123456"
+	},
+	"custom_data": {
+		"event_source": "crm",
+		"lead_event_source": "Salesforce",
+		"stage": 2
+	},
+	"event_time": 1700000000
+}`
+
+// goldenOfflineEvent is the event of an OfflineEvent, as facebook expects it. Fields of the event take precedence
+// over CustomData.
+const goldenOfflineEvent = `{
+	"event_name": "Purchase",
+	"event_id": "order-1",
+	"action_source": "physical_store",
+	"user_data": {
+		"client_ip_address": "192.0.2.1"
+	},
+	"custom_data": {
+		"contents": [{"id": "sku-1", "quantity": 2}],
+		"currency": "EUR",
+		"order_id": "order-1",
+		"store": "copenhagen",
+		"value": 42.5
+	},
+	"event_time": 1700000000
+}`
+
+func TestOfflineEventsServerEvent(t *testing.T) {
+	crm := CRMLeadEvent{
+		Stage:           "Qualified",
+		EventTime:       time.Unix(1700000000, 0),
+		EventID:         "lead-1-qualified",
+		LeadID:          "1234567890123456",
+		LeadEventSource: "Salesforce",
+		UserData:        UserData{ClientIPAddress: "192.0.2.1", LeadID: "9999999999999999"},
+		Custom:          map[string]interface{}{"stage": 2},
+	}
+
+	offline := OfflineEvent{
+		EventName: "Purchase",
+		EventTime: time.Unix(1700000000, 0),
+		EventID:   "order-1",
+		UserData:  UserData{ClientIPAddress: "192.0.2.1"},
+		Value:     42.5,
+		Currency:  "EUR",
+		OrderID:   "order-1",
+		Contents:  []Content{{ID: "sku-1", Quantity: 2}},
+		CustomData: &CustomData{
+			Value:    1,
+			Currency: "USD",
+			OrderID:  "order-0",
+			Custom:   map[string]interface{}{"store": "copenhagen"},
+		},
+	}
+
+	cases := map[string]struct {
+		event  ServerEvent
+		golden string
+	}{
+		"crm":     {event: crm.ServerEvent(), golden: goldenCRMLeadEvent},
+		"offline": {event: offline.ServerEvent(), golden: goldenOfflineEvent},
+	}
+
+	for name, c := range cases {
+		golden := &bytes.Buffer{}
+		if err := json.Compact(golden, []byte(c.golden)); err != nil {
+			t.Fatalf("invalid golden json. [case:%v] [e:%v]", name, err)
+		}
+
+		data, err := json.Marshal(c.event)
+
+		if err != nil || string(data) != golden.String() {
+			t.Fatalf("unexpected json. [case:%v] [e:%v] [expected:%s] [actual:%s]", name, err, golden, data)
+		}
+	}
+
+	if offline.CustomData.Value != 1 {
+		t.Fatalf("custom data of the offline event should not be changed. [custom_data:%+v]", offline.CustomData)
+	}
+
+	if event := (OfflineEvent{EventName: "Visit"}).ServerEvent(); event.CustomData != nil {
+		t.Fatalf("empty custom data should be omitted. [custom_data:%+v]", event.CustomData)
+	}
+}
+
+func TestEventUploadParams(t *testing.T) {
+	cases := map[EventUpload]string{
+		{}:                               "map[]",
+		{Tag: "store_purchases"}:         "map[upload_tag:store_purchases]",
+		{ID: "u1", Source: "salesforce"}: "map[upload_id:u1 upload_source:salesforce]",
+		{Tag: "t", ID: "u1", Source: "s", NamespaceID: "n"}: "map[namespace_id:n upload_id:u1 upload_source:s upload_tag:t]",
+	}
+
+	for upload, expected := range cases {
+		if params := upload.Params(); fmt.Sprint(params) != expected {
+			t.Fatalf("unexpected params. [upload:%+v] [expected:%v] [actual:%v]", upload, expected, params)
+		}
+	}
+}
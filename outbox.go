@@ -0,0 +1,377 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOutboxInterval   = time.Second
+	defaultOutboxMinBackoff = time.Second
+	defaultOutboxMaxBackoff = 5 * time.Minute
+)
+
+// Outbox persists server events until they are delivered by an OutboxSender.
+// Events are delivered in the order they are pushed.
+//
+// Implementations must be safe for concurrent use, with any number of producers calling Push
+// and a single OutboxSender calling Peek and Remove.
+type Outbox interface {
+	// Push appends events to the outbox.
+	Push(events ...ServerEvent) error
+
+	// Peek returns up to n oldest events without removing them.
+	Peek(n int) ([]ServerEvent, error)
+
+	// Remove removes the n oldest events.
+	Remove(n int) error
+
+	// Len returns the number of events in the outbox.
+	Len() (int, error)
+}
+
+// MemoryOutbox is an Outbox keeping events in memory. Events are lost when the process exits.
+type MemoryOutbox struct {
+	mu     sync.Mutex
+	events []ServerEvent
+}
+
+var _ Outbox = (*MemoryOutbox)(nil)
+
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{}
+}
+
+func (o *MemoryOutbox) Push(events ...ServerEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func (o *MemoryOutbox) Peek(n int) ([]ServerEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]ServerEvent(nil), o.events[:min(n, len(o.events))]...), nil
+}
+
+func (o *MemoryOutbox) Remove(n int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// copy the rest so that removed events can be garbage collected.
+	o.events = append([]ServerEvent(nil), o.events[min(n, len(o.events)):]...)
+	return nil
+}
+
+func (o *MemoryOutbox) Len() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.events), nil
+}
+
+type OutboxOptions struct {
+	BatchSize  int           // events per call, at most MaxEventsPerUpload. defaults to MaxEventsPerUpload.
+	Interval   time.Duration // delay between checks of an empty outbox. defaults to 1s.
+	MinBackoff time.Duration // delay after the first failed delivery, doubling after every failure. defaults to 1s.
+	MaxBackoff time.Duration // upper bound of the delay after failed deliveries. defaults to 5m.
+	Params     Params        // extra params sent with every call.
+}
+
+// OutboxStats counts events handled by an OutboxSender.
+type OutboxStats struct {
+	Pending      int       // events in the outbox.
+	Delivered    int       // events facebook received.
+	Dropped      int       // events removed as they became too old for facebook to accept them.
+	Rejected     int       // events removed as facebook rejected them as invalid, while accepting other events.
+	Suppressed   int       // events removed as they were already sent, see WithDeduplicator.
+	Failures     int       // failed deliveries, which are retried.
+	LastError    error     // error of the last failed or rejected delivery, or of remembering delivered events.
+	LastDelivery time.Time // time of the last successful delivery.
+}
+
+// OutboxSender delivers events of an Outbox to a dataset in the background.
+//
+// Failed deliveries are retried with exponential backoff, and events are kept in the outbox until
+// facebook receives them. Events older than MaxEventAge, or MaxPhysicalStoreEventAge for physical
+// store events, are dropped. Events facebook rejects as invalid are dropped as well, so that they
+// don't block the outbox; they are counted in OutboxStats.Rejected. When facebook rejects a batch,
+// its events passing ServerEvent.Validate and then halves of rejected groups are sent again to find
+// the invalid events. Events are only rejected once facebook accepted others, so that a wrong dataset
+// or a missing permission fails the delivery instead.
+type OutboxSender struct {
+	client    *Client
+	datasetID string
+	outbox    Outbox
+	opts      OutboxOptions
+
+	mu    sync.Mutex
+	stats OutboxStats
+}
+
+// NewOutboxSender creates an OutboxSender delivering events of outbox to datasetID with c.
+func (c *Client) NewOutboxSender(datasetID string, outbox Outbox, opts OutboxOptions) *OutboxSender {
+	if opts.BatchSize <= 0 || opts.BatchSize > MaxEventsPerUpload {
+		opts.BatchSize = MaxEventsPerUpload
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = defaultOutboxInterval
+	}
+
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultOutboxMinBackoff
+	}
+
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultOutboxMaxBackoff
+	}
+
+	return &OutboxSender{
+		client:    c,
+		datasetID: datasetID,
+		outbox:    outbox,
+		opts:      opts,
+	}
+}
+
+// Run delivers events until ctx is done, and then returns ctx.Err(). Call it in its own goroutine.
+func (s *OutboxSender) Run(ctx context.Context) error {
+	backoff := s.opts.MinBackoff
+
+	for {
+		n, err := s.deliver(ctx)
+		delay := s.opts.Interval
+
+		switch {
+		case err != nil:
+			delay = backoff
+			backoff = min(backoff*2, s.opts.MaxBackoff)
+		case n > 0:
+			delay = 0
+			backoff = s.opts.MinBackoff
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Flush delivers events until the outbox is empty, without retrying failed deliveries.
+func (s *OutboxSender) Flush(ctx context.Context) error {
+	for {
+		n, err := s.deliver(ctx)
+		if err != nil || n == 0 {
+			return err
+		}
+	}
+}
+
+// Stats returns the current delivery stats.
+func (s *OutboxSender) Stats() OutboxStats {
+	s.mu.Lock()
+	stats := s.stats
+	s.mu.Unlock()
+
+	stats.Pending, _ = s.outbox.Len()
+	return stats
+}
+
+// deliver sends the oldest batch of events and returns the number of events removed from the outbox.
+func (s *OutboxSender) deliver(ctx context.Context) (int, error) {
+	events, err := s.outbox.Peek(s.opts.BatchSize)
+	if err != nil || len(events) == 0 {
+		return 0, s.fail(err)
+	}
+
+	now := time.Now()
+	fresh := make([]ServerEvent, 0, len(events))
+
+	for _, event := range events {
+		if !eventExpired(event, now) {
+			fresh = append(fresh, event)
+		}
+	}
+
+	dropped := len(events) - len(fresh)
+	var result outboxUpload
+
+	if len(fresh) > 0 {
+		params := make(Params, len(s.opts.Params)+1)
+		for k, v := range s.opts.Params {
+			params[k] = v
+		}
+
+		if result, err = s.upload(ctx, fresh, params); err != nil {
+			return 0, s.fail(err)
+		}
+	}
+
+	if err := s.outbox.Remove(len(events)); err != nil {
+		return 0, s.fail(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Dropped += dropped
+	s.stats.Rejected += result.rejected
+	s.stats.Delivered += result.delivered
+	s.stats.Suppressed += result.suppressed
+
+	if result.delivered > 0 {
+		s.stats.LastDelivery = now
+	}
+
+	if result.lastErr != nil {
+		s.stats.LastError = result.lastErr
+	}
+
+	return len(events), nil
+}
+
+// outboxUpload is the outcome of OutboxSender.upload.
+type outboxUpload struct {
+	delivered  int
+	suppressed int
+	rejected   int
+	accepted   bool  // whether facebook accepted any call.
+	lastErr    error // error of the last rejected call, or of remembering delivered events.
+}
+
+// upload sends events. If facebook rejects them as invalid, they are sent again in groups to find which
+// events are invalid: first the events passing ServerEvent.Validate, and then halves of rejected groups.
+//
+// Invalid events are rejected only once facebook accepted another group, which shows that the dataset
+// and permissions are fine, as facebook rejects calls for them with the same error. Otherwise the delivery
+// fails with the first error and is retried.
+func (s *OutboxSender) upload(ctx context.Context, events []ServerEvent, params Params) (outboxUpload, error) {
+	var result outboxUpload
+
+	send := func(group []ServerEvent) (bool, error) {
+		out, err := s.client.UploadServerEvents(ctx, s.datasetID, group, params)
+
+		var fbErr *Error
+
+		switch {
+		case err == nil:
+			result.delivered += out.EventsReceived
+			result.suppressed += out.Suppressed
+			result.accepted = true
+
+			if out.MarkErr != nil {
+				result.lastErr = out.MarkErr
+			}
+
+			return true, nil
+		case errors.As(err, &fbErr) && isInvalidEventsError(fbErr):
+			result.lastErr = err
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+
+	if ok, err := send(events); ok || err != nil {
+		return result, err
+	}
+
+	firstErr := result.lastErr
+	now := time.Now()
+	var valid, invalid []ServerEvent
+
+	for _, event := range events {
+		if event.validate(now) == nil {
+			valid = append(valid, event)
+		} else {
+			invalid = append(invalid, event)
+		}
+	}
+
+	// rejected groups to split, the next one last.
+	var groups [][]ServerEvent
+
+	if len(valid) > 0 && len(invalid) > 0 {
+		ok, err := send(valid)
+		if err != nil {
+			return result, err
+		}
+
+		if !ok {
+			groups = append(groups, valid)
+		}
+	} else {
+		groups, invalid = append(groups, events), nil
+	}
+
+	for len(groups) > 0 {
+		group := groups[len(groups)-1]
+		groups = groups[:len(groups)-1]
+
+		if len(group) == 1 {
+			// every group sent so far was rejected, which is more likely a problem of the dataset.
+			if !result.accepted {
+				break
+			}
+
+			invalid = append(invalid, group...)
+			continue
+		}
+
+		half := len(group) / 2
+
+		// the first half is pushed last, so that it's split first.
+		for _, part := range [][]ServerEvent{group[half:], group[:half]} {
+			ok, err := send(part)
+			if err != nil {
+				return result, err
+			}
+
+			if !ok {
+				groups = append(groups, part)
+			}
+		}
+	}
+
+	if !result.accepted {
+		return result, firstErr
+	}
+
+	result.rejected = len(invalid)
+	return result, nil
+}
+
+func (s *OutboxSender) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Failures++
+	s.stats.LastError = err
+	return err
+}
+
+// eventExpired reports whether an event is too old for facebook to accept it.
+func eventExpired(event ServerEvent, now time.Time) bool {
+	return now.Sub(event.EventTime) > maxEventAge(event.ActionSource)
+}
+
+// isInvalidEventsError reports whether facebook rejects events as invalid, so that sending them again fails as well.
+func isInvalidEventsError(e *Error) bool {
+	// code 100 is "Invalid parameter".
+	return e.Code == 100 && !e.IsTransient
+}
@@ -0,0 +1,325 @@
+package facebook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compactOutboxSize is the size of delivered events above which a FileOutbox rewrites its file.
+const compactOutboxSize = 1 << 20
+
+// FileOutbox is an Outbox persisting events in a file, one JSON event per line, so that pending events
+// survive restarts. The position of the oldest event is kept in a second file with the ".offset" suffix.
+//
+// Events are delivered at least once: events removed right before a crash may be delivered again.
+// Facebook deduplicates them if they have an event_id.
+//
+// Lines which can't be decoded, e.g. after a disk failure, don't block the outbox. They are moved to
+// a third file with the ".corrupt" suffix when they become the oldest lines, and counted by Corrupt.
+type FileOutbox struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	offset  int64 // position of the oldest event.
+	size    int64 // position after the newest event.
+	count   int   // number of events after offset.
+	corrupt int   // number of lines moved to the corrupt file.
+}
+
+var _ Outbox = (*FileOutbox)(nil)
+
+// OpenFileOutbox opens or creates the outbox stored at path.
+// An event partially written when the process crashed is discarded.
+func OpenFileOutbox(path string) (*FileOutbox, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("facebook: cannot open outbox; %w", err)
+	}
+
+	o := &FileOutbox{path: path, file: file}
+
+	if err := o.load(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("facebook: cannot load outbox %s; %w", path, err)
+	}
+
+	return o, nil
+}
+
+func (o *FileOutbox) load() error {
+	data, err := os.ReadFile(o.offsetPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(data) > 0 {
+		if o.offset, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return fmt.Errorf("invalid offset; %w", err)
+		}
+	}
+
+	info, err := o.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	o.offset = min(o.offset, info.Size())
+	reader := bufio.NewReader(io.NewSectionReader(o.file, o.offset, info.Size()-o.offset))
+	o.size = o.offset
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		o.size += int64(len(line))
+		o.count++
+	}
+
+	// drop a partially written event.
+	if o.size < info.Size() {
+		return o.file.Truncate(o.size)
+	}
+
+	return nil
+}
+
+func (o *FileOutbox) Push(events ...ServerEvent) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, err := o.file.WriteAt(buf.Bytes(), o.size); err != nil {
+		return err
+	}
+
+	if err := o.file.Sync(); err != nil {
+		return err
+	}
+
+	o.size += int64(buf.Len())
+	o.count += len(events)
+	return nil
+}
+
+// Peek returns up to n oldest events. Undecodable lines before the first event are moved to the
+// corrupt file, and Peek stops at undecodable lines after it, so that Remove removes the events returned.
+func (o *FileOutbox) Peek(n int) ([]ServerEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if n <= 0 {
+		return nil, nil
+	}
+
+	var events []ServerEvent
+	var corrupt []byte
+	var numCorrupt int
+
+	err := o.scan(func(line []byte) bool {
+		var event ServerEvent
+
+		if err := json.Unmarshal(line, &event); err != nil {
+			if len(events) > 0 {
+				return false
+			}
+
+			corrupt = append(corrupt, line...)
+			numCorrupt++
+			return true
+		}
+
+		events = append(events, event)
+		return len(events) < n
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if numCorrupt > 0 {
+		if err := o.moveCorrupt(corrupt, numCorrupt); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+func (o *FileOutbox) Remove(n int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if n <= 0 {
+		return nil
+	}
+
+	offset := o.offset
+	removed := 0
+
+	err := o.scan(func(line []byte) bool {
+		offset += int64(len(line))
+		removed++
+		return removed < n
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := o.writeOffset(offset); err != nil {
+		return err
+	}
+
+	o.offset = offset
+	o.count -= removed
+
+	if o.offset >= compactOutboxSize && o.offset > o.size/2 {
+		return o.compact()
+	}
+
+	return nil
+}
+
+func (o *FileOutbox) Len() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.count, nil
+}
+
+// Corrupt returns the number of undecodable lines moved to the corrupt file since the outbox was opened.
+func (o *FileOutbox) Corrupt() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.corrupt
+}
+
+// Close closes the file of the outbox.
+func (o *FileOutbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.file.Close()
+}
+
+// scan calls fn with the oldest lines, including their line break, until fn returns false.
+func (o *FileOutbox) scan(fn func(line []byte) bool) error {
+	reader := bufio.NewReader(io.NewSectionReader(o.file, o.offset, o.size-o.offset))
+
+	for {
+		line, err := reader.ReadBytes('\n')
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !fn(line) {
+			return nil
+		}
+	}
+}
+
+// moveCorrupt appends the n oldest lines, which can't be decoded, to the corrupt file and removes them.
+func (o *FileOutbox) moveCorrupt(lines []byte, n int) error {
+	file, err := os.OpenFile(o.path+".corrupt", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(lines); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	offset := o.offset + int64(len(lines))
+
+	if err := o.writeOffset(offset); err != nil {
+		return err
+	}
+
+	o.offset = offset
+	o.count -= n
+	o.corrupt += n
+	return nil
+}
+
+// compact rewrites the file without removed events.
+func (o *FileOutbox) compact() error {
+	tmpPath := o.path + ".tmp"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tmp, io.NewSectionReader(o.file, o.offset, o.size-o.offset)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	// reset the offset first, so that a crash delivers events again rather than skipping them.
+	if err := o.writeOffset(0); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	_ = o.file.Close()
+	o.file = tmp
+	o.size -= o.offset
+	o.offset = 0
+	return nil
+}
+
+func (o *FileOutbox) offsetPath() string {
+	return o.path + ".offset"
+}
+
+func (o *FileOutbox) writeOffset(offset int64) error {
+	tmpPath := o.offsetPath() + ".tmp"
+
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatInt(offset, 10)), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, o.offsetPath())
+}
@@ -0,0 +1,270 @@
+package facebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileOutboxReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.outbox")
+
+	outbox, err := OpenFileOutbox(path)
+	if err != nil {
+		t.Fatalf("cannot open outbox. [e:%v]", err)
+	}
+
+	if err := outbox.Push(testEvents("e", 3)...); err != nil {
+		t.Fatalf("cannot push events. [e:%v]", err)
+	}
+
+	if err := outbox.Remove(1); err != nil {
+		t.Fatalf("cannot remove events. [e:%v]", err)
+	}
+
+	_ = outbox.Close()
+
+	// a crash while writing an event leaves a partial line.
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	_, _ = file.WriteString(`{"event_name": "Lead", "event_id": "partial"`)
+	_ = file.Close()
+
+	outbox, err = OpenFileOutbox(path)
+	if err != nil {
+		t.Fatalf("cannot reopen outbox. [e:%v]", err)
+	}
+	defer outbox.Close()
+
+	if n, _ := outbox.Len(); n != 2 {
+		t.Fatalf("removed and partial events should not be pending. [len:%v]", n)
+	}
+
+	if err := outbox.Push(testEvents("f", 1)...); err != nil {
+		t.Fatalf("cannot push events. [e:%v]", err)
+	}
+
+	events, err := outbox.Peek(10)
+	if err != nil || eventIDs(events) != "e1 e2 f0" {
+		t.Fatalf("unexpected events after reopening. [events:%v] [e:%v]", eventIDs(events), err)
+	}
+}
+
+func TestFileOutboxCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.outbox")
+
+	outbox, err := OpenFileOutbox(path)
+	if err != nil {
+		t.Fatalf("cannot open outbox. [e:%v]", err)
+	}
+	defer outbox.Close()
+
+	events := testEvents("e", 100)
+	for i := range events {
+		events[i].EventSourceURL = "https://example.com/" + strings.Repeat("x", 20000)
+	}
+
+	if err := outbox.Push(events...); err != nil {
+		t.Fatalf("cannot push events. [e:%v]", err)
+	}
+
+	if err := outbox.Remove(40); err != nil {
+		t.Fatalf("cannot remove events. [e:%v]", err)
+	}
+
+	if info, _ := os.Stat(path); info.Size() < compactOutboxSize {
+		t.Fatalf("file should not be compacted while most events are pending. [size:%v]", info.Size())
+	}
+
+	if err := outbox.Remove(30); err != nil {
+		t.Fatalf("cannot remove events. [e:%v]", err)
+	}
+
+	if info, _ := os.Stat(path); info.Size() > compactOutboxSize {
+		t.Fatalf("file should be compacted. [size:%v]", info.Size())
+	}
+
+	if offset, _ := os.ReadFile(path + ".offset"); string(offset) != "0" {
+		t.Fatalf("offset should be reset by compaction. [offset:%s]", offset)
+	}
+
+	_ = outbox.Push(testEvents("f", 1)...)
+
+	peeked, err := outbox.Peek(100)
+	if n, _ := outbox.Len(); err != nil || n != 31 || len(peeked) != 31 || peeked[0].EventID != "e70" || peeked[30].EventID != "f0" {
+		t.Fatalf("pending events should be kept. [len:%v] [peeked:%v] [e:%v]", n, len(peeked), err)
+	}
+
+	reopened, err := OpenFileOutbox(path)
+	if err != nil {
+		t.Fatalf("cannot reopen outbox. [e:%v]", err)
+	}
+	defer reopened.Close()
+
+	if n, _ := reopened.Len(); n != 31 {
+		t.Fatalf("compacted outbox should be reopened. [len:%v]", n)
+	}
+}
+
+func TestFileOutboxCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.outbox")
+	lines := []string{`{"event_id": "e0"}`, `garbage`, `{"event_id": "e1"}`, `{"event_id": 1}`, `{"event_id": "e2"}`}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("cannot write outbox. [e:%v]", err)
+	}
+
+	outbox, err := OpenFileOutbox(path)
+	if err != nil {
+		t.Fatalf("cannot open outbox. [e:%v]", err)
+	}
+	defer outbox.Close()
+
+	var ids []string
+
+	for {
+		events, err := outbox.Peek(10)
+		if err != nil {
+			t.Fatalf("undecodable lines should not fail peek. [e:%v]", err)
+		}
+
+		if len(events) == 0 {
+			break
+		}
+
+		ids = append(ids, eventIDs(events))
+		_ = outbox.Remove(len(events))
+	}
+
+	if fmt.Sprint(ids) != "[e0 e1 e2]" {
+		t.Fatalf("peek should stop before undecodable lines. [peeked:%v]", ids)
+	}
+
+	corrupt, _ := os.ReadFile(path + ".corrupt")
+
+	if n, _ := outbox.Len(); n != 0 || outbox.Corrupt() != 2 || string(corrupt) != "garbage\n{\"event_id\": 1}\n" {
+		t.Fatalf("undecodable lines should be moved aside. [len:%v] [corrupt:%v] [file:%q]", n, outbox.Corrupt(), corrupt)
+	}
+}
+
+func TestOutboxSenderDropsExpiredEvents(t *testing.T) {
+	srv := newEventsTestServer(t)
+	client := newTestClient(srv.Server)
+
+	events := testEvents("e", 3)
+	events[1].EventTime = time.Now().Add(-MaxEventAge - time.Hour)
+	events[2].ActionSource = ActionSourcePhysicalStore
+	events[2].EventTime = time.Now().Add(-MaxEventAge - time.Hour)
+
+	outbox := NewMemoryOutbox()
+	_ = outbox.Push(events...)
+	sender := client.NewOutboxSender("d1", outbox, OutboxOptions{})
+
+	if err := sender.Flush(context.Background()); err != nil {
+		t.Fatalf("flush should succeed. [e:%v]", err)
+	}
+
+	if stats, received := sender.Stats(), srv.Received(); fmt.Sprint(received) != "[e0 e2]" || stats.Dropped != 1 || stats.Delivered != 2 || stats.Pending != 0 {
+		t.Fatalf("expired events should be dropped. [received:%v] [stats:%+v]", received, stats)
+	}
+}
+
+func TestOutboxSenderRejectedEvents(t *testing.T) {
+	invalid := testEvents("missing", 1)
+	invalid[0].UserData = UserData{}
+
+	cases := []struct {
+		name     string
+		events   []ServerEvent
+		received string
+		rejected int
+		maxCalls int
+	}{
+		{
+			name:     "bad event found by bisecting",
+			events:   append(append(testEvents("a", 5), testEvents("bad", 1)...), testEvents("b", 6)...),
+			received: "[a0 a1 a2 a3 a4 b0 b1 b2 b3 b4 b5]",
+			rejected: 1,
+			maxCalls: 1 + 2*4,
+		},
+		{
+			name:     "locally invalid event",
+			events:   append(append(testEvents("bad", 1), testEvents("a", 3)...), invalid...),
+			received: "[a0 a1 a2]",
+			rejected: 2,
+		},
+		{
+			name:     "bad events in both halves",
+			events:   append(append(testEvents("bad", 1), testEvents("a", 6)...), testEvents("bad", 1)...),
+			received: "[a0 a1 a2 a3 a4 a5]",
+			rejected: 2,
+		},
+	}
+
+	for _, c := range cases {
+		srv := newEventsTestServer(t)
+		client := newTestClient(srv.Server)
+
+		outbox := NewMemoryOutbox()
+		_ = outbox.Push(c.events...)
+		sender := client.NewOutboxSender("d1", outbox, OutboxOptions{})
+
+		if err := sender.Flush(context.Background()); err != nil {
+			t.Fatalf("flush should succeed. [case:%v] [e:%v]", c.name, err)
+		}
+
+		stats, received, calls := sender.Stats(), srv.Received(), len(srv.Calls())
+		slices.Sort(received)
+
+		if fmt.Sprint(received) != c.received || stats.Rejected != c.rejected || stats.Delivered != len(received) || stats.Pending != 0 {
+			t.Fatalf("only invalid events should be rejected. [case:%v] [received:%v] [stats:%+v]", c.name, received, stats)
+		}
+
+		if stats.Failures != 0 || stats.LastError == nil {
+			t.Fatalf("rejections should not count as failures. [case:%v] [stats:%+v]", c.name, stats)
+		}
+
+		if c.maxCalls > 0 && calls > c.maxCalls {
+			t.Fatalf("too many calls to find invalid events. [case:%v] [calls:%v]", c.name, calls)
+		}
+	}
+}
+
+func TestOutboxSenderRetriesRejectedDataset(t *testing.T) {
+	srv := newEventsTestServer(t)
+	client := newTestClient(srv.Server)
+
+	cases := map[string][]ServerEvent{
+		"valid events":  testEvents("a", 16),
+		"single event":  testEvents("a", 1),
+		"invalid event": append(testEvents("a", 2), ServerEvent{EventName: "Lead", EventTime: time.Now(), ActionSource: ActionSourceEmail}),
+	}
+
+	for name, events := range cases {
+		srv.Reset()
+		outbox := NewMemoryOutbox()
+		_ = outbox.Push(events...)
+		sender := client.NewOutboxSender("unknown", outbox, OutboxOptions{})
+
+		var fbErr *Error
+
+		if err := sender.Flush(context.Background()); !errors.As(err, &fbErr) || fbErr.Code != 100 {
+			t.Fatalf("delivery should fail with facebook's error. [case:%v] [e:%v]", name, err)
+		}
+
+		if stats := sender.Stats(); stats.Pending != len(events) || stats.Rejected != 0 || stats.Failures != 1 {
+			t.Fatalf("events rejected by a wrong dataset should be retried. [case:%v] [stats:%+v]", name, stats)
+		}
+
+		// every split sends both halves, and only the first half is split further.
+		if calls, limit := len(srv.Calls()), 2+2*bits.Len(uint(len(events))); calls > limit {
+			t.Fatalf("too many calls to a wrong dataset. [case:%v] [calls:%v]", name, calls)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package facebook
+
+import (
+	"context"
+	"github.com/dreamdata-io/facebook/internal"
+	"iter"
+)
+
+type PagingResult = internal.PagingResult
+type PagingLimit = internal.PagingLimit
+
+// Paging creates a PagingResult from a paging response, e.g. the result of CustomAudiences,
+// which reads next pages with ctx.
+func (c *Client) Paging(ctx context.Context, res Result) (*PagingResult, error) {
+	return res.Paging(c.session.WithContext(ctx))
+}
+
+// Pages iterates over the data of all pages in pr. See PagingResult.Pages.
+func Pages(pr *PagingResult, limit PagingLimit) iter.Seq2[[]Result, error] {
+	return pr.Pages(limit)
+}
+
+// All iterates over the items of all pages in pr decoded into T with Result.Decode.
+// It stops at the first error.
+func All[T any](pr *PagingResult, limit PagingLimit) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range pr.Pages(limit) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page {
+				var v T
+
+				if err := item.Decode(&v); err != nil {
+					yield(v, err)
+					return
+				}
+
+				if !yield(v, nil) {
+					return
+				}
+			}
+		}
+	}
+}
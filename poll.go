@@ -0,0 +1,56 @@
+package facebook
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultPollMaxInterval = time.Minute
+)
+
+// PollOptions controls how operations running asynchronously on facebook's side are polled.
+type PollOptions struct {
+	Interval    time.Duration // delay between the first and the second check. defaults to 1s. the first check runs immediately.
+	MaxInterval time.Duration // upper bound of the delay, which doubles after every poll. defaults to 1m.
+	Timeout     time.Duration // gives up polling after this long. zero polls until ctx is done.
+}
+
+// poll calls check until it reports done, fails, or polling times out.
+func (o PollOptions) poll(ctx context.Context, check func(ctx context.Context) (done bool, err error)) error {
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	interval := o.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	maxInterval := o.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultPollMaxInterval
+	}
+
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("facebook: stop polling; %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
@@ -9,6 +9,10 @@ type Result = internal.Result
 type Method = internal.Method
 type Params = internal.Params
 type Error = internal.Error
+type RetryPolicy = internal.RetryPolicy
+type RetryAttempt = internal.RetryAttempt
+type Throttler = internal.Throttler
+type ThrottledError = internal.ThrottledError
 
 func FieldsParams(fields ...string) Params {
 	return internal.MakeParams(map[string]string{
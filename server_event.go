@@ -0,0 +1,330 @@
+package facebook
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type ActionSource = string
+
+// Action sources of server events.
+const (
+	ActionSourceEmail             ActionSource = "email"
+	ActionSourceWebsite           ActionSource = "website"
+	ActionSourceApp               ActionSource = "app"
+	ActionSourcePhoneCall         ActionSource = "phone_call"
+	ActionSourceChat              ActionSource = "chat"
+	ActionSourcePhysicalStore     ActionSource = "physical_store"
+	ActionSourceSystemGenerated   ActionSource = "system_generated"
+	ActionSourceBusinessMessaging ActionSource = "business_messaging"
+	ActionSourceOther             ActionSource = "other"
+)
+
+// ServerEvent is an event sent with the Conversions API.
+// See https://developers.facebook.com/docs/marketing-api/conversions-api/parameters/server-event.
+type ServerEvent struct {
+	EventName      string       `json:"event_name"`
+	EventTime      time.Time    `json:"-"` // sent as a unix timestamp. must be set.
+	EventID        string       `json:"event_id,omitempty"`
+	EventSourceURL string       `json:"event_source_url,omitempty"`
+	ActionSource   ActionSource `json:"action_source"`
+	OptOut         bool         `json:"opt_out,omitempty"`
+	UserData       UserData     `json:"user_data"`
+	CustomData     *CustomData  `json:"custom_data,omitempty"`
+	AppData        *AppData     `json:"app_data,omitempty"`
+	ReferrerURL    string       `json:"referrer_url,omitempty"`
+
+	DataProcessingOptions        []string `json:"data_processing_options,omitempty"`
+	DataProcessingOptionsCountry int      `json:"data_processing_options_country,omitempty"`
+	DataProcessingOptionsState   int      `json:"data_processing_options_state,omitempty"`
+}
+
+type serverEvent ServerEvent
+
+func (e ServerEvent) MarshalJSON() ([]byte, error) {
+	if e.EventTime.IsZero() {
+		return nil, errors.New("facebook: event_time of server event is not set")
+	}
+
+	return json.Marshal(struct {
+		serverEvent
+		EventTime int64 `json:"event_time"`
+	}{
+		serverEvent: serverEvent(e),
+		EventTime:   e.EventTime.Unix(),
+	})
+}
+
+// UnmarshalJSON decodes an event encoded by MarshalJSON. Customer information stays hashed.
+func (e *ServerEvent) UnmarshalJSON(data []byte) error {
+	var v struct {
+		serverEvent
+		EventTime int64 `json:"event_time"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*e = ServerEvent(v.serverEvent)
+	e.EventTime = time.Unix(v.EventTime, 0)
+	return nil
+}
+
+// UserData holds customer information of a server event.
+//
+// Customer information is normalized and hashed with SHA-256 when encoded, following
+// https://developers.facebook.com/docs/marketing-api/conversions-api/parameters/customer-information-parameters.
+// Values which are already hashed are sent as is. Client IP address, user agent, click ID and browser ID
+// are never hashed.
+type UserData struct {
+	// Hashed fields.
+	Emails      []string
+	Phones      []string // including country code.
+	FirstName   string
+	LastName    string
+	Gender      string
+	DateOfBirth string // YYYYMMDD.
+	City        string
+	State       string // 2-letter state code.
+	Zip         string
+	Country     string // 2-letter ISO 3166-1 alpha-2 country code.
+	ExternalIDs []string
+
+	// Fields sent as is.
+	ClientIPAddress string
+	ClientUserAgent string
+	FBC             string // click ID stored in the _fbc cookie.
+	FBP             string // browser ID stored in the _fbp cookie.
+	SubscriptionID  string
+	FBLoginID       string
+	LeadID          string
+}
+
+type userDataJSON struct {
+	Emails      []string `json:"em,omitempty"`
+	Phones      []string `json:"ph,omitempty"`
+	FirstName   string   `json:"fn,omitempty"`
+	LastName    string   `json:"ln,omitempty"`
+	Gender      string   `json:"ge,omitempty"`
+	DateOfBirth string   `json:"db,omitempty"`
+	City        string   `json:"ct,omitempty"`
+	State       string   `json:"st,omitempty"`
+	Zip         string   `json:"zp,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	ExternalIDs []string `json:"external_id,omitempty"`
+
+	ClientIPAddress string `json:"client_ip_address,omitempty"`
+	ClientUserAgent string `json:"client_user_agent,omitempty"`
+	FBC             string `json:"fbc,omitempty"`
+	FBP             string `json:"fbp,omitempty"`
+	SubscriptionID  string `json:"subscription_id,omitempty"`
+	FBLoginID       string `json:"fb_login_id,omitempty"`
+	LeadID          string `json:"lead_id,omitempty"`
+}
+
+func (u UserData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(userDataJSON{
+		Emails:      hashValues(u.Emails, normalizeEmail),
+		Phones:      hashValues(u.Phones, normalizePhone),
+		FirstName:   hashValue(u.FirstName, normalizeName),
+		LastName:    hashValue(u.LastName, normalizeName),
+		Gender:      hashValue(u.Gender, normalizeGender),
+		DateOfBirth: hashValue(u.DateOfBirth, normalizeDate),
+		City:        hashValue(u.City, normalizeCity),
+		State:       hashValue(u.State, normalizeState),
+		Zip:         hashValue(u.Zip, normalizeZip),
+		Country:     hashValue(u.Country, normalizeCountry),
+		ExternalIDs: hashValues(u.ExternalIDs, normalizeExternalID),
+
+		ClientIPAddress: u.ClientIPAddress,
+		ClientUserAgent: u.ClientUserAgent,
+		FBC:             u.FBC,
+		FBP:             u.FBP,
+		SubscriptionID:  u.SubscriptionID,
+		FBLoginID:       u.FBLoginID,
+		LeadID:          u.LeadID,
+	})
+}
+
+// UnmarshalJSON decodes user data encoded by MarshalJSON. Customer information stays hashed.
+func (u *UserData) UnmarshalJSON(data []byte) error {
+	var v userDataJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*u = UserData(v)
+	return nil
+}
+
+// CustomData holds business data of a server event.
+// See https://developers.facebook.com/docs/marketing-api/conversions-api/parameters/custom-data.
+type CustomData struct {
+	Value            float64   `json:"value,omitempty"`
+	Currency         string    `json:"currency,omitempty"`
+	ContentName      string    `json:"content_name,omitempty"`
+	ContentCategory  string    `json:"content_category,omitempty"`
+	ContentIDs       []string  `json:"content_ids,omitempty"`
+	Contents         []Content `json:"contents,omitempty"`
+	ContentType      string    `json:"content_type,omitempty"`
+	OrderID          string    `json:"order_id,omitempty"`
+	PredictedLTV     float64   `json:"predicted_ltv,omitempty"`
+	NumItems         int       `json:"num_items,omitempty"`
+	SearchString     string    `json:"search_string,omitempty"`
+	Status           string    `json:"status,omitempty"`
+	DeliveryCategory string    `json:"delivery_category,omitempty"`
+
+	// CRM events, see CRMLeadEvent.
+	EventSource     string `json:"event_source,omitempty"`      // "crm".
+	LeadEventSource string `json:"lead_event_source,omitempty"` // name of the CRM, e.g. "Salesforce".
+
+	// Custom holds custom properties sent along with the standard ones.
+	// Standard properties take precedence over custom properties with the same name.
+	Custom map[string]interface{} `json:"-"`
+}
+
+type customData CustomData
+
+// customDataKeys holds the names of standard custom data properties.
+var customDataKeys = jsonFieldNames(reflect.TypeOf(CustomData{}))
+
+func (d CustomData) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(customData(d))
+	if err != nil || len(d.Custom) == 0 {
+		return data, err
+	}
+
+	merged := make(map[string]interface{}, len(d.Custom))
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+
+	for k, v := range d.Custom {
+		if _, ok := merged[k]; !ok {
+			merged[k] = v
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON decodes custom data. Properties other than the standard ones are kept in Custom.
+func (d *CustomData) UnmarshalJSON(data []byte) error {
+	var standard customData
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for k := range customDataKeys {
+		delete(all, k)
+	}
+
+	*d = CustomData(standard)
+
+	if len(all) > 0 {
+		d.Custom = all
+	}
+
+	return nil
+}
+
+// Content is a product in CustomData.Contents.
+type Content struct {
+	ID               string  `json:"id"`
+	Quantity         int     `json:"quantity,omitempty"`
+	ItemPrice        float64 `json:"item_price,omitempty"`
+	DeliveryCategory string  `json:"delivery_category,omitempty"`
+	Title            string  `json:"title,omitempty"`
+	Description      string  `json:"description,omitempty"`
+	Brand            string  `json:"brand,omitempty"`
+	Category         string  `json:"category,omitempty"`
+}
+
+// AppData holds app information of a server event with the "app" action source.
+// See https://developers.facebook.com/docs/marketing-api/conversions-api/app-events.
+type AppData struct {
+	AdvertiserTrackingEnabled  bool     `json:"-"`
+	ApplicationTrackingEnabled bool     `json:"-"`
+	ExtInfo                    []string `json:"extinfo"`
+	CampaignIDs                string   `json:"campaign_ids,omitempty"`
+	InstallReferrer            string   `json:"install_referrer,omitempty"`
+	InstallerPackage           string   `json:"installer_package,omitempty"`
+	URLSchemes                 []string `json:"url_schemes,omitempty"`
+	WindowsAttributionID       string   `json:"windows_attribution_id,omitempty"`
+}
+
+type appData AppData
+
+func (d AppData) MarshalJSON() ([]byte, error) {
+	// facebook expects tracking flags as 0 or 1.
+	return json.Marshal(struct {
+		appData
+		AdvertiserTrackingEnabled  int `json:"advertiser_tracking_enabled"`
+		ApplicationTrackingEnabled int `json:"application_tracking_enabled"`
+	}{
+		appData:                    appData(d),
+		AdvertiserTrackingEnabled:  boolToInt(d.AdvertiserTrackingEnabled),
+		ApplicationTrackingEnabled: boolToInt(d.ApplicationTrackingEnabled),
+	})
+}
+
+// UnmarshalJSON decodes app data encoded by MarshalJSON.
+func (d *AppData) UnmarshalJSON(data []byte) error {
+	var v struct {
+		appData
+		AdvertiserTrackingEnabled  int `json:"advertiser_tracking_enabled"`
+		ApplicationTrackingEnabled int `json:"application_tracking_enabled"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*d = AppData(v.appData)
+	d.AdvertiserTrackingEnabled = v.AdvertiserTrackingEnabled == 1
+	d.ApplicationTrackingEnabled = v.ApplicationTrackingEnabled == 1
+	return nil
+}
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func hashValues(values []string, normalize func(string) string) []string {
+	var hashed []string
+
+	for _, v := range values {
+		if h := hashValue(v, normalize); h != "" {
+			hashed = append(hashed, h)
+		}
+	}
+
+	return hashed
+}
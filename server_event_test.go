@@ -0,0 +1,136 @@
+package facebook
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func newGoldenServerEvent() ServerEvent {
+	return ServerEvent{
+		EventName:      "Purchase",
+		EventTime:      time.Unix(1700000000, 0),
+		EventID:        "order-1",
+		EventSourceURL: "https://example.com/checkout",
+		ActionSource:   ActionSourceApp,
+		UserData: UserData{
+			Emails:          []string{" John.Doe@Example.com"},
+			Phones:          []string{"+1 (650) 555-1212"},
+			FirstName:       "John",
+			LastName:        "O'Doe",
+			Gender:          "Male",
+			DateOfBirth:     "1990-01-31",
+			City:            "New York",
+			State:           "NY",
+			Zip:             "10001-1234",
+			Country:         "US",
+			ExternalIDs:     []string{"user-1"},
+			ClientIPAddress: "192.0.2.1",
+			ClientUserAgent: "Mozilla/5.0",
+			FBC:             "fb.1.1554763741205.AbCdEfGhIjKlMnOpQrStUvWxYz1234567890",
+			FBP:             "fb.1.1558571054389.1098115397",
+			LeadID:          "1234567890123456",
+		},
+		CustomData: &CustomData{
+			Value:    42.5,
+			Currency: "USD",
+			Contents: []Content{{ID: "sku-1", Quantity: 2}},
+			Custom:   map[string]interface{}{"coupon": "SPRING", "value": 1},
+		},
+		AppData: &AppData{
+			AdvertiserTrackingEnabled: true,
+			ExtInfo:                   []string{"a2", "com.example.app"},
+		},
+	}
+}
+
+// goldenServerEvent is newGoldenServerEvent as facebook expects it, with customer information
+// normalized and hashed. Standard custom data properties take precedence over custom ones.
+const goldenServerEvent = `{
+	"event_name": "Purchase",
+	"event_id": "order-1",
+	"event_source_url": "https://example.com/checkout",
+	"action_source": "app",
+	"user_data": {
+		"em": ["836f82db99121b3481011f16b49dfa5fbc714a0d1b1b9f784a1ebbbf5b39577f"],
+		"ph": ["e323ec626319ca94ee8bff2e4c87cf613be6ea19919ed1364124e16807ab3176"],
+		"fn": "96d9632f363564cc3032521409cf22a852f2032eec099ed5967c0d000cec607a",
+		"ln": "fff1833cf87ec12a259fbf6b5bee308dc19f4a8cd0407451f402f973a5ec8059",
+		"ge": "62c66a7a5dd70c3146618063c344e531e6d4b59e379808443ce962b3abd63c5a",
+		"db": "267b39628557f31a6766b3d7f8823978c276759be4a5c9c52bf868d802ba5a0d",
+		"ct": "350c754ba4d38897693aa077ef43072a859d23f613443133fecbbd90a3512ca5",
+		"st": "1b06e2003f8420d6fa42badd8f77ec0f706b976b7a48b13c567dc5a559681683",
+		"zp": "e443169117a184f91186b401133b20be670c7c0896f9886075e5d9b81e9d076b",
+		"country": "79adb2a2fce5c6ba215fe5f27f532d4e7edbac4b6a5e09e1ef3a08084a904621",
+		"external_id": ["c6c289e49e9c05b2145860387b73bcb18df43fb09a1e4a4a9713c76c88bb541b"],
+		"client_ip_address": "192.0.2.1",
+		"client_user_agent": "Mozilla/5.0",
+		"fbc": "fb.1.1554763741205.AbCdEfGhIjKlMnOpQrStUvWxYz1234567890",
+		"fbp": "fb.1.1558571054389.1098115397",
+		"lead_id": "1234567890123456"
+	},
+	"custom_data": {
+		"contents": [{"id": "sku-1", "quantity": 2}],
+		"coupon": "SPRING",
+		"currency": "USD",
+		"value": 42.5
+	},
+	"app_data": {
+		"extinfo": ["a2", "com.example.app"],
+		"advertiser_tracking_enabled": 1,
+		"application_tracking_enabled": 0
+	},
+	"event_time": 1700000000
+}`
+
+func TestServerEventMarshalJSON(t *testing.T) {
+	golden := &bytes.Buffer{}
+	if err := json.Compact(golden, []byte(goldenServerEvent)); err != nil {
+		t.Fatalf("invalid golden json. [e:%v]", err)
+	}
+
+	data, err := json.Marshal(newGoldenServerEvent())
+
+	if err != nil {
+		t.Fatalf("cannot marshal event. [e:%v]", err)
+	}
+
+	if string(data) != golden.String() {
+		t.Fatalf("unexpected json. [expected:%s] [actual:%s]", golden, data)
+	}
+
+	// decoded events keep hashed values, so that they are encoded the same way again.
+	var event ServerEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("cannot unmarshal event. [e:%v]", err)
+	}
+
+	if !event.EventTime.Equal(time.Unix(1700000000, 0)) || !event.AppData.AdvertiserTrackingEnabled || event.CustomData.Custom["coupon"] != "SPRING" {
+		t.Fatalf("unexpected decoded event. [event:%+v]", event)
+	}
+
+	if _, ok := event.CustomData.Custom["value"]; ok {
+		t.Fatalf("standard properties should not be decoded as custom ones. [custom:%v]", event.CustomData.Custom)
+	}
+
+	again, err := json.Marshal(event)
+
+	if err != nil || string(again) != golden.String() {
+		t.Fatalf("decoded event should be encoded the same way. [e:%v] [json:%s]", err, again)
+	}
+}
+
+func TestServerEventMarshalJSONWithoutTime(t *testing.T) {
+	if _, err := json.Marshal(ServerEvent{EventName: "Lead"}); err == nil {
+		t.Fatalf("event without event_time should not be encoded.")
+	}
+}
+
+func TestUserDataMarshalJSONEmpty(t *testing.T) {
+	data, err := json.Marshal(UserData{Emails: []string{" "}, Gender: "unknown"})
+
+	if err != nil || string(data) != "{}" {
+		t.Fatalf("empty values should be omitted. [e:%v] [json:%s]", err, data)
+	}
+}
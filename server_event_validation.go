@@ -0,0 +1,173 @@
+package facebook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// MaxEventAge is how old events facebook accepts can be, except physical store events.
+	MaxEventAge = 7 * 24 * time.Hour
+
+	// MaxPhysicalStoreEventAge is how old physical store events facebook accepts can be.
+	MaxPhysicalStoreEventAge = 62 * 24 * time.Hour
+
+	// eventTimeSkew tolerates clocks slightly ahead of facebook's.
+	eventTimeSkew = time.Minute
+)
+
+var actionSources = map[ActionSource]bool{
+	ActionSourceEmail: true, ActionSourceWebsite: true, ActionSourceApp: true, ActionSourcePhoneCall: true,
+	ActionSourceChat: true, ActionSourcePhysicalStore: true, ActionSourceSystemGenerated: true,
+	ActionSourceBusinessMessaging: true, ActionSourceOther: true,
+}
+
+// ServerEventError reports an invalid event in a slice of events.
+type ServerEventError struct {
+	Index     int // position of the event.
+	EventName string
+	Err       error
+}
+
+func (e *ServerEventError) Error() string {
+	return fmt.Sprintf("facebook: invalid server event %d (%s); %v", e.Index, e.EventName, e.Err)
+}
+
+func (e *ServerEventError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks an event without sending it. It reports:
+//
+//   - missing event name, event time or unknown action source;
+//   - event times in the future or older than MaxEventAge, or MaxPhysicalStoreEventAge for physical store events;
+//   - missing fields required by the action source, e.g. event_source_url and client_user_agent for website events;
+//   - missing event_id on website and app events, which facebook needs to deduplicate them from browser and SDK events;
+//   - missing customer information, and missing value or currency on purchases;
+//   - CRM events without the "system_generated" action source or lead_event_source, and invalid lead_id.
+//
+// All problems are joined in the returned error.
+func (e ServerEvent) Validate() error {
+	return e.validate(time.Now())
+}
+
+func (e ServerEvent) validate(now time.Time) error {
+	var errs []error
+
+	if e.EventName == "" {
+		errs = append(errs, errors.New("event_name is empty"))
+	}
+
+	if e.EventTime.IsZero() {
+		errs = append(errs, errors.New("event_time is not set"))
+	} else {
+		maxAge := maxEventAge(e.ActionSource)
+
+		if e.EventTime.After(now.Add(eventTimeSkew)) {
+			errs = append(errs, fmt.Errorf("event_time %s is in the future", e.EventTime.Format(time.RFC3339)))
+		} else if now.Sub(e.EventTime) > maxAge {
+			errs = append(errs, fmt.Errorf("event_time %s is older than %v", e.EventTime.Format(time.RFC3339), maxAge))
+		}
+	}
+
+	if !actionSources[e.ActionSource] {
+		errs = append(errs, fmt.Errorf("action_source %q is unknown", e.ActionSource))
+	}
+
+	switch e.ActionSource {
+	case ActionSourceWebsite:
+		if e.EventSourceURL == "" {
+			errs = append(errs, errors.New("event_source_url is required for website events"))
+		}
+
+		if e.UserData.ClientUserAgent == "" {
+			errs = append(errs, errors.New("client_user_agent is required for website events"))
+		}
+	case ActionSourceApp:
+		if e.AppData == nil {
+			errs = append(errs, errors.New("app_data is required for app events"))
+		} else if len(e.AppData.ExtInfo) == 0 {
+			errs = append(errs, errors.New("app_data.extinfo is required for app events"))
+		}
+	}
+
+	if e.EventID == "" && (e.ActionSource == ActionSourceWebsite || e.ActionSource == ActionSourceApp) {
+		errs = append(errs, errors.New("event_id is required to deduplicate website and app events"))
+	}
+
+	if !e.UserData.hasCustomerInformation() {
+		errs = append(errs, errors.New("user_data has no customer information"))
+	}
+
+	if e.CustomData != nil && e.CustomData.EventSource == CRMEventSource {
+		errs = append(errs, e.validateCRM()...)
+	}
+
+	if e.EventName == "Purchase" && (e.CustomData == nil || e.CustomData.Currency == "" || e.CustomData.Value == 0) {
+		errs = append(errs, errors.New("custom_data.currency and value are required for purchases"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e ServerEvent) validateCRM() []error {
+	var errs []error
+
+	if e.ActionSource != ActionSourceSystemGenerated {
+		errs = append(errs, fmt.Errorf("action_source of CRM events must be %q", ActionSourceSystemGenerated))
+	}
+
+	if e.CustomData.LeadEventSource == "" {
+		errs = append(errs, errors.New("custom_data.lead_event_source is required for CRM events"))
+	}
+
+	if id := e.UserData.LeadID; id != "" && (len(id) < 15 || len(id) > 17 || strings.ContainsFunc(id, notDigit)) {
+		errs = append(errs, fmt.Errorf("lead_id %q must have 15 to 17 digits", id))
+	}
+
+	return errs
+}
+
+// maxEventAge returns how old events of an action source facebook accepts can be.
+func maxEventAge(source ActionSource) time.Duration {
+	if source == ActionSourcePhysicalStore {
+		return MaxPhysicalStoreEventAge
+	}
+
+	return MaxEventAge
+}
+
+func (u UserData) hasCustomerInformation() bool {
+	fields := []string{u.FirstName, u.LastName, u.Gender, u.DateOfBirth, u.City, u.State, u.Zip, u.Country,
+		u.ClientIPAddress, u.ClientUserAgent, u.FBC, u.FBP, u.SubscriptionID, u.FBLoginID, u.LeadID}
+
+	for _, v := range fields {
+		if v != "" {
+			return true
+		}
+	}
+
+	return len(u.Emails) > 0 || len(u.Phones) > 0 || len(u.ExternalIDs) > 0
+}
+
+// ValidateServerEvents validates all events with ServerEvent.Validate.
+// The returned error joins a *ServerEventError per invalid event.
+func ValidateServerEvents(events []ServerEvent) error {
+	now := time.Now()
+	var errs []error
+
+	for i, event := range events {
+		if err := event.validate(now); err != nil {
+			errs = append(errs, &ServerEventError{Index: i, EventName: event.EventName, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func notDigit(r rune) bool {
+	return !unicode.IsDigit(r)
+}
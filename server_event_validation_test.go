@@ -0,0 +1,117 @@
+package facebook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerEventValidate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	website := func(e *ServerEvent) {
+		e.ActionSource = ActionSourceWebsite
+		e.EventSourceURL = "https://example.com"
+		e.UserData.ClientUserAgent = "Mozilla/5.0"
+	}
+
+	app := func(e *ServerEvent) {
+		e.ActionSource = ActionSourceApp
+		e.AppData = &AppData{ExtInfo: []string{"a2"}}
+	}
+
+	crm := func(e *ServerEvent) {
+		e.ActionSource = ActionSourceSystemGenerated
+		e.CustomData = &CustomData{EventSource: CRMEventSource, LeadEventSource: "Salesforce"}
+		e.UserData = UserData{LeadID: "1234567890123456"}
+	}
+
+	// err is a part of the expected error, empty for valid events.
+	cases := []struct {
+		name   string
+		update func(e *ServerEvent)
+		err    string
+	}{
+		{name: "valid", update: func(e *ServerEvent) {}},
+		{name: "no event name", update: func(e *ServerEvent) { e.EventName = "" }, err: "event_name is empty"},
+		{name: "no event time", update: func(e *ServerEvent) { e.EventTime = time.Time{} }, err: "event_time is not set"},
+		{name: "within time skew", update: func(e *ServerEvent) { e.EventTime = now.Add(30 * time.Second) }},
+		{name: "future", update: func(e *ServerEvent) { e.EventTime = now.Add(2 * time.Minute) }, err: "is in the future"},
+		{name: "7 days old", update: func(e *ServerEvent) { e.EventTime = now.Add(-MaxEventAge) }},
+		{name: "older than 7 days", update: func(e *ServerEvent) { e.EventTime = now.Add(-MaxEventAge - time.Second) }, err: "is older than"},
+		{name: "physical store 62 days old", update: func(e *ServerEvent) {
+			e.ActionSource, e.EventTime = ActionSourcePhysicalStore, now.Add(-MaxPhysicalStoreEventAge)
+		}},
+		{name: "physical store older than 62 days", update: func(e *ServerEvent) {
+			e.ActionSource, e.EventTime = ActionSourcePhysicalStore, now.Add(-MaxPhysicalStoreEventAge-time.Second)
+		}, err: "is older than"},
+		{name: "unknown action source", update: func(e *ServerEvent) { e.ActionSource = "web" }, err: `action_source "web" is unknown`},
+		{name: "website", update: website},
+		{name: "website without url", update: func(e *ServerEvent) { website(e); e.EventSourceURL = "" }, err: "event_source_url is required"},
+		{name: "website without user agent", update: func(e *ServerEvent) { website(e); e.UserData.ClientUserAgent = "" }, err: "client_user_agent is required"},
+		{name: "website without event id", update: func(e *ServerEvent) { website(e); e.EventID = "" }, err: "event_id is required"},
+		{name: "app", update: app},
+		{name: "app without app data", update: func(e *ServerEvent) { app(e); e.AppData = nil }, err: "app_data is required"},
+		{name: "app without extinfo", update: func(e *ServerEvent) { app(e); e.AppData.ExtInfo = nil }, err: "app_data.extinfo is required"},
+		{name: "app without event id", update: func(e *ServerEvent) { app(e); e.EventID = "" }, err: "event_id is required"},
+		{name: "email without event id", update: func(e *ServerEvent) { e.EventID = "" }},
+		{name: "no customer information", update: func(e *ServerEvent) { e.UserData = UserData{} }, err: "no customer information"},
+		{name: "purchase", update: func(e *ServerEvent) {
+			e.EventName, e.CustomData = "Purchase", &CustomData{Value: 42.5, Currency: "USD"}
+		}},
+		{name: "purchase without custom data", update: func(e *ServerEvent) { e.EventName = "Purchase" }, err: "currency and value are required"},
+		{name: "purchase without value", update: func(e *ServerEvent) {
+			e.EventName, e.CustomData = "Purchase", &CustomData{Currency: "USD"}
+		}, err: "currency and value are required"},
+		{name: "purchase without currency", update: func(e *ServerEvent) {
+			e.EventName, e.CustomData = "Purchase", &CustomData{Value: 42.5}
+		}, err: "currency and value are required"},
+		{name: "crm", update: crm},
+		{name: "crm lead id with 15 digits", update: func(e *ServerEvent) { crm(e); e.UserData.LeadID = "123456789012345" }},
+		{name: "crm lead id with 17 digits", update: func(e *ServerEvent) { crm(e); e.UserData.LeadID = "12345678901234567" }},
+		{name: "crm lead id with 14 digits", update: func(e *ServerEvent) { crm(e); e.UserData.LeadID = "12345678901234" }, err: "must have 15 to 17 digits"},
+		{name: "crm lead id with 18 digits", update: func(e *ServerEvent) { crm(e); e.UserData.LeadID = "123456789012345678" }, err: "must have 15 to 17 digits"},
+		{name: "crm lead id with letters", update: func(e *ServerEvent) { crm(e); e.UserData.LeadID = "12345678901234a" }, err: "must have 15 to 17 digits"},
+		{name: "crm without lead event source", update: func(e *ServerEvent) { crm(e); e.CustomData.LeadEventSource = "" }, err: "lead_event_source is required"},
+		{name: "crm from email", update: func(e *ServerEvent) { crm(e); e.ActionSource = ActionSourceEmail }, err: `must be "system_generated"`},
+	}
+
+	for _, c := range cases {
+		event := testEvents("e", 1)[0]
+		event.EventTime = now.Add(-time.Hour)
+		c.update(&event)
+
+		err := event.validate(now)
+
+		if c.err == "" && err != nil || c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("unexpected validation. [case:%v] [expected:%v] [e:%v]", c.name, c.err, err)
+		}
+	}
+}
+
+func TestUploadEventsTestEventCode(t *testing.T) {
+	var codes []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		codes = append(codes, r.FormValue("test_event_code"))
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"events_received": 1}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.testEventCode = "TEST1"
+
+	for _, params := range []Params{nil, {"data": "[]"}, {"test_event_code": "TEST2"}} {
+		if _, err := client.UploadEvents(context.Background(), "d1", params); err != nil {
+			t.Fatalf("upload should succeed. [params:%v] [e:%v]", params, err)
+		}
+	}
+
+	if strings.Join(codes, " ") != "TEST1 TEST1 TEST2" {
+		t.Fatalf("client test event code should be sent unless set in params. [codes:%v]", codes)
+	}
+}